@@ -0,0 +1,202 @@
+package timestone
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// afterFuncer is implemented by contexts that can run a func once they
+// are done, as recognized by the context package to propagate
+// cancellation synchronously.
+type afterFuncer interface {
+	AfterFunc(f func()) (stop func() bool)
+}
+
+// WithDeadline is like context.WithDeadline, but deadline is measured by
+// the Clock of scheduler. The returned context.Context is cancelled with
+// context.DeadlineExceeded by an Action scheduled via
+// Scheduler.PerformAfter with tags, so that with a simulation.Scheduler
+// the context expires once Forward passes deadline, ordered like any
+// other event at the same time.
+//
+// Calling the returned context.CancelFunc releases the scheduled Action.
+func WithDeadline(parent context.Context, scheduler Scheduler, deadline time.Time, tags ...string) (context.Context, context.CancelFunc) {
+	if parentDeadline, ok := parent.Deadline(); ok && !parentDeadline.After(deadline) {
+		return context.WithCancel(parent)
+	}
+
+	ctx := newDeadlineContext(parent, deadline)
+
+	if parent.Err() != nil {
+		ctx.cancel(parent.Err(), context.Cause(parent))
+		return ctx, func() {}
+	}
+
+	if !deadline.After(scheduler.Now()) {
+		ctx.cancel(context.DeadlineExceeded, context.DeadlineExceeded)
+		return ctx, func() {}
+	}
+
+	stopPropagation := ctx.propagateCancel(parent)
+
+	actionCtx, cancelAction := context.WithCancel(parent)
+	scheduler.PerformAfter(
+		actionCtx,
+		SimpleAction(func(context.Context) {
+			stopPropagation()
+			ctx.cancel(context.DeadlineExceeded, context.DeadlineExceeded)
+		}),
+		deadline.Sub(scheduler.Now()),
+		tags...,
+	)
+
+	return ctx, func() {
+		stopPropagation()
+		cancelAction()
+		ctx.cancel(context.Canceled, context.Canceled)
+	}
+}
+
+// WithTimeout returns WithDeadline(parent, scheduler,
+// scheduler.Now().Add(timeout), tags...).
+func WithTimeout(parent context.Context, scheduler Scheduler, timeout time.Duration, tags ...string) (context.Context, context.CancelFunc) {
+	return WithDeadline(parent, scheduler, scheduler.Now().Add(timeout), tags...)
+}
+
+// AfterFunc is like context.AfterFunc, but if ctx has been returned by
+// WithDeadline or WithTimeout, or carries values on top of such a
+// context, f is called synchronously when ctx is cancelled. With a simulation.Scheduler, f is thus called as part of
+// the event that lets ctx expire, rather than in a goroutine racing with
+// the following events.
+func AfterFunc(ctx context.Context, f func()) (stop func() bool) {
+	// Like the context package, see through contexts that are done along
+	// with a deadlineContext, e.g. those returned by context.WithValue.
+	if deadlineCtx, ok := ctx.Value(deadlineContextKey{}).(*deadlineContext); ok && deadlineCtx.Done() == ctx.Done() {
+		return deadlineCtx.AfterFunc(f)
+	}
+
+	return context.AfterFunc(ctx, f)
+}
+
+// deadlineContextKey is the key under which a deadlineContext returns
+// itself as value.
+type deadlineContextKey struct{}
+
+// deadlineContext is the context.Context returned by WithDeadline. It
+// embeds a context.Context derived by context.WithCancelCause, so that
+// context.Cause reports why it has been cancelled.
+type deadlineContext struct {
+	context.Context
+	cancelCause context.CancelCauseFunc
+
+	deadline time.Time
+
+	done       chan struct{}
+	err        error
+	afterFuncs []*func()
+	mu         sync.Mutex
+}
+
+func newDeadlineContext(parent context.Context, deadline time.Time) *deadlineContext {
+	ctx, cancelCause := context.WithCancelCause(parent)
+
+	return &deadlineContext{
+		Context:     ctx,
+		cancelCause: cancelCause,
+		deadline:    deadline,
+		done:        make(chan struct{}),
+	}
+}
+
+func (d *deadlineContext) Deadline() (time.Time, bool) {
+	return d.deadline, true
+}
+
+func (d *deadlineContext) Done() <-chan struct{} {
+	return d.done
+}
+
+func (d *deadlineContext) Value(key any) any {
+	if key == (deadlineContextKey{}) {
+		return d
+	}
+
+	return d.Context.Value(key)
+}
+
+func (d *deadlineContext) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.err
+}
+
+// AfterFunc arranges to call f synchronously once d is cancelled, or in
+// its own goroutine if d is already cancelled. Children derived from d
+// via the context package rely on it to be cancelled along with d.
+func (d *deadlineContext) AfterFunc(f func()) (stop func() bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.err != nil {
+		go f()
+		return func() bool { return false }
+	}
+
+	registered := &f
+	d.afterFuncs = append(d.afterFuncs, registered)
+
+	return func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		index := slices.Index(d.afterFuncs, registered)
+		if index < 0 {
+			return false
+		}
+
+		d.afterFuncs = slices.Delete(d.afterFuncs, index, index+1)
+
+		return true
+	}
+}
+
+func (d *deadlineContext) cancel(err, cause error) {
+	d.mu.Lock()
+
+	if d.err != nil {
+		d.mu.Unlock()
+		return
+	}
+
+	d.err = err
+	d.cancelCause(cause)
+	close(d.done)
+
+	afterFuncs := d.afterFuncs
+	d.afterFuncs = nil
+
+	d.mu.Unlock()
+
+	for _, f := range afterFuncs {
+		(*f)()
+	}
+}
+
+// propagateCancel cancels d along with parent, synchronously if parent
+// supports it.
+func (d *deadlineContext) propagateCancel(parent context.Context) (stop func() bool) {
+	cancelWithParent := func() { d.cancel(parent.Err(), context.Cause(parent)) }
+
+	if parent, ok := parent.(afterFuncer); ok {
+		return parent.AfterFunc(cancelWithParent)
+	}
+
+	return context.AfterFunc(parent, cancelWithParent)
+}
+
+func (d *deadlineContext) String() string {
+	return "timestone.WithDeadline(" + d.deadline.String() + ")"
+}
@@ -0,0 +1,212 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSpec = errors.New("invalid cron spec")
+
+type field struct {
+	name     string
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	secondField = field{name: "second", min: 0, max: 59}
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// Parse parses a cron spec into a Schedule. A spec consists of either
+// five fields (minute, hour, day of month, month, day of week) or six
+// fields with a leading second field. Each field supports lists (1,2),
+// ranges (1-5), steps (*/15, 10-40/10, 5/10) and, for months and days of
+// week, three letter names. The descriptors @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly are accepted as well.
+//
+// The spec may be prefixed with CRON_TZ=<location> or TZ=<location> to
+// evaluate it in the given time zone. Otherwise it will be evaluated in
+// the location of the time passed to Schedule.Next.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	var location *time.Location
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		zone, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(zone, "=")
+
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unknown time zone %q: %v", ErrInvalidSpec, name, err)
+		}
+
+		location = loc
+		spec = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := descriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown descriptor %q", ErrInvalidSpec, spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: expected 5 or 6 fields, got %d in %q", ErrInvalidSpec, len(fields), spec)
+	}
+
+	schedule := &Schedule{location: location}
+
+	var err error
+	if schedule.second, err = parseField(fields[0], secondField); err != nil {
+		return nil, err
+	}
+	if schedule.minute, err = parseField(fields[1], minuteField); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseField(fields[2], hourField); err != nil {
+		return nil, err
+	}
+	if schedule.dom, err = parseField(fields[3], domField); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseField(fields[4], monthField); err != nil {
+		return nil, err
+	}
+	if schedule.dow, err = parseField(fields[5], dowField); err != nil {
+		return nil, err
+	}
+
+	// Sunday may be given as 0 or 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow = schedule.dow&^(1<<7) | 1
+	}
+
+	schedule.domRestricted = !isWildcard(fields[3])
+	schedule.dowRestricted = !isWildcard(fields[5])
+
+	return schedule, nil
+}
+
+// MustParse is like Parse but panics if spec can't be parsed.
+func MustParse(spec string) *Schedule {
+	schedule, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return schedule
+}
+
+func isWildcard(expression string) bool {
+	return expression == "*" || expression == "?"
+}
+
+func parseField(expression string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(expression, ",") {
+		partBits, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+
+		bits |= partBits
+	}
+
+	return bits, nil
+}
+
+func parseRange(expression string, f field) (uint64, error) {
+	rangeExpression, stepExpression, hasStep := strings.Cut(expression, "/")
+
+	var start, end uint
+	switch {
+	case isWildcard(rangeExpression):
+		start, end = f.min, f.max
+	default:
+		lowExpression, highExpression, isRange := strings.Cut(rangeExpression, "-")
+
+		var err error
+		if start, err = parseValue(lowExpression, f); err != nil {
+			return 0, err
+		}
+
+		switch {
+		case isRange:
+			if end, err = parseValue(highExpression, f); err != nil {
+				return 0, err
+			}
+		case hasStep:
+			end = f.max
+		default:
+			end = start
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("%w: %s range %q is reversed", ErrInvalidSpec, f.name, expression)
+	}
+
+	step := uint(1)
+	if hasStep {
+		parsedStep, err := strconv.ParseUint(stepExpression, 10, 32)
+		if err != nil || parsedStep == 0 {
+			return 0, fmt.Errorf("%w: invalid %s step in %q", ErrInvalidSpec, f.name, expression)
+		}
+		step = uint(parsedStep)
+	}
+
+	var bits uint64
+	for value := start; value <= end; value += step {
+		bits |= 1 << value
+	}
+
+	return bits, nil
+}
+
+func parseValue(expression string, f field) (uint, error) {
+	if value, ok := f.names[strings.ToLower(expression)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.ParseUint(expression, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s value %q", ErrInvalidSpec, f.name, expression)
+	}
+
+	if uint(value) < f.min || uint(value) > f.max {
+		return 0, fmt.Errorf("%w: %s value %d out of range [%d, %d]", ErrInvalidSpec, f.name, value, f.min, f.max)
+	}
+
+	return uint(value), nil
+}
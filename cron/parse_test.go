@@ -0,0 +1,193 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bits(values ...uint) uint64 {
+	var result uint64
+	for _, value := range values {
+		result |= 1 << value
+	}
+
+	return result
+}
+
+func bitRange(from, to, step uint) uint64 {
+	var result uint64
+	for value := from; value <= to; value += step {
+		result |= 1 << value
+	}
+
+	return result
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		spec       string
+		want       *Schedule
+		requireErr bool
+	}{
+		{
+			name: "five fields",
+			spec: "30 2 * * *",
+			want: &Schedule{
+				second: bits(0),
+				minute: bits(30),
+				hour:   bits(2),
+				dom:    bitRange(1, 31, 1),
+				month:  bitRange(1, 12, 1),
+				dow:    bitRange(0, 6, 1),
+			},
+		},
+		{
+			name: "six fields",
+			spec: "*/15 0 12 1,15 * ?",
+			want: &Schedule{
+				second:        bits(0, 15, 30, 45),
+				minute:        bits(0),
+				hour:          bits(12),
+				dom:           bits(1, 15),
+				month:         bitRange(1, 12, 1),
+				dow:           bitRange(0, 6, 1),
+				domRestricted: true,
+			},
+		},
+		{
+			name: "names, ranges and steps",
+			spec: "0 10-40/10 8 * jan-MAR MON-fri",
+			want: &Schedule{
+				second:        bits(0),
+				minute:        bits(10, 20, 30, 40),
+				hour:          bits(8),
+				dom:           bitRange(1, 31, 1),
+				month:         bits(1, 2, 3),
+				dow:           bits(1, 2, 3, 4, 5),
+				dowRestricted: true,
+			},
+		},
+		{
+			name: "start with step",
+			spec: "5/20 * * * *",
+			want: &Schedule{
+				second: bits(0),
+				minute: bits(5, 25, 45),
+				hour:   bitRange(0, 23, 1),
+				dom:    bitRange(1, 31, 1),
+				month:  bitRange(1, 12, 1),
+				dow:    bitRange(0, 6, 1),
+			},
+		},
+		{
+			name: "sunday as seven",
+			spec: "0 0 * * 7",
+			want: &Schedule{
+				second:        bits(0),
+				minute:        bits(0),
+				hour:          bits(0),
+				dom:           bitRange(1, 31, 1),
+				month:         bitRange(1, 12, 1),
+				dow:           bits(0),
+				dowRestricted: true,
+			},
+		},
+		{
+			name: "descriptor",
+			spec: "@daily",
+			want: &Schedule{
+				second: bits(0),
+				minute: bits(0),
+				hour:   bits(0),
+				dom:    bitRange(1, 31, 1),
+				month:  bitRange(1, 12, 1),
+				dow:    bitRange(0, 6, 1),
+			},
+		},
+		{
+			name: "time zone",
+			spec: "CRON_TZ=Europe/Berlin 0 2 * * *",
+			want: &Schedule{
+				second:   bits(0),
+				minute:   bits(0),
+				hour:     bits(2),
+				dom:      bitRange(1, 31, 1),
+				month:    bitRange(1, 12, 1),
+				dow:      bitRange(0, 6, 1),
+				location: berlin,
+			},
+		},
+		{
+			name:       "unknown time zone",
+			spec:       "TZ=Mars/Olympus 0 2 * * *",
+			requireErr: true,
+		},
+		{
+			name:       "unknown descriptor",
+			spec:       "@fortnightly",
+			requireErr: true,
+		},
+		{
+			name:       "too few fields",
+			spec:       "* * * *",
+			requireErr: true,
+		},
+		{
+			name:       "too many fields",
+			spec:       "* * * * * * *",
+			requireErr: true,
+		},
+		{
+			name:       "value out of range",
+			spec:       "0 24 * * *",
+			requireErr: true,
+		},
+		{
+			name:       "reversed range",
+			spec:       "0 10-5 * * *",
+			requireErr: true,
+		},
+		{
+			name:       "zero step",
+			spec:       "*/0 * * * *",
+			requireErr: true,
+		},
+		{
+			name:       "not a number",
+			spec:       "foo * * * *",
+			requireErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.spec)
+
+			if tt.requireErr {
+				require.ErrorIs(t, err, ErrInvalidSpec)
+				require.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	t.Parallel()
+
+	require.NotNil(t, MustParse("* * * * *"))
+	require.Panics(t, func() { MustParse("* * *") })
+}
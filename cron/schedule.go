@@ -0,0 +1,168 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// searchLimitYears bounds the search for the next firing, so that specs
+// which never match, like "0 0 30 2 *", terminate.
+const searchLimitYears = 5
+
+var _ timestone.Schedule = (*Schedule)(nil)
+
+// Schedule is a parsed cron spec. Use Parse or MustParse to create one.
+// It implements timestone.Schedule.
+type Schedule struct {
+	second, minute, hour, dom, month, dow uint64
+
+	// Following cron conventions, if both day of month and day of week
+	// are restricted, a day matches if either of them matches.
+	domRestricted, dowRestricted bool
+
+	location *time.Location
+}
+
+// Next returns the first firing of the Schedule strictly after after.
+// The second return value is false if the Schedule doesn't fire anymore.
+//
+// Wall clock times skipped by a daylight saving time transition don't
+// fire, while wall clock times repeated by a transition fire only once.
+func (s *Schedule) Next(after time.Time) (time.Time, bool) {
+	location := s.location
+	if location == nil {
+		location = after.Location()
+	}
+
+	next, ok := s.next(after.In(location), location)
+	for ok && s.isRepeatedWallClock(next) {
+		next, ok = s.next(next, location)
+	}
+
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return next.In(after.Location()), true
+}
+
+func (s *Schedule) next(after time.Time, location *time.Location) (time.Time, bool) {
+	t := after.Add(time.Second - time.Duration(after.Nanosecond()))
+	yearLimit := t.Year() + searchLimitYears
+
+	// Once a field has been incremented, all less significant fields
+	// need to be reset to their minimum.
+	added := false
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}, false
+	}
+
+	for 1<<uint(t.Month())&s.month == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, location)
+		}
+		t = t.AddDate(0, 1, 0)
+
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+		}
+		t = t.AddDate(0, 0, 1)
+
+		// Midnight might have been skipped or repeated by a daylight
+		// saving time transition.
+		if t.Hour() != 0 {
+			if t.Hour() > 12 {
+				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+			} else {
+				t = t.Add(time.Duration(-t.Hour()) * time.Hour)
+			}
+		}
+
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for 1<<uint(t.Hour())&s.hour == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, location)
+		}
+		day := t.Day()
+		t = t.Add(time.Hour)
+
+		// Compare the day rather than checking for hour 0, as midnight
+		// might have been skipped by a daylight saving time transition.
+		if t.Day() != day {
+			goto wrap
+		}
+	}
+
+	for 1<<uint(t.Minute())&s.minute == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for 1<<uint(t.Second())&s.second == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+		t = t.Add(time.Second)
+
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t, true
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatches := 1<<uint(t.Day())&s.dom != 0
+	dowMatches := 1<<uint(t.Weekday())&s.dow != 0
+
+	if s.domRestricted && s.dowRestricted {
+		return domMatches || dowMatches
+	}
+
+	return domMatches && dowMatches
+}
+
+// isRepeatedWallClock reports whether the wall clock time of t has
+// already occurred before t because of a daylight saving time transition.
+func (s *Schedule) isRepeatedWallClock(t time.Time) bool {
+	_, offset := t.Zone()
+
+	for _, lookBack := range []time.Duration{30 * time.Minute, time.Hour, 2 * time.Hour} {
+		_, earlierOffset := t.Add(-lookBack).Zone()
+		if earlierOffset <= offset {
+			continue
+		}
+
+		earlier := t.Add(-time.Duration(earlierOffset-offset) * time.Second)
+		if earlier.Format(time.DateTime) == t.Format(time.DateTime) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,165 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	santiago, err := time.LoadLocation("America/Santiago")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		spec   string
+		after  time.Time
+		want   []time.Time
+		wantOk bool
+	}{
+		{
+			name:  "every minute",
+			spec:  "* * * * *",
+			after: time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 12, 2, 0, 0, time.UTC),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "every 20 seconds",
+			spec:  "*/20 * * * * *",
+			after: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 1, 1, 12, 0, 20, 0, time.UTC),
+				time.Date(2024, 1, 1, 12, 0, 40, 0, time.UTC),
+				time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "wrap around year",
+			spec:  "0 0 1 1 *",
+			after: time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "day of month or day of week",
+			spec:  "0 0 13 * FRI",
+			after: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 9, 6, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 9, 13, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 9, 20, 0, 0, 0, 0, time.UTC),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "leap day",
+			spec:  "0 0 29 2 *",
+			after: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+			wantOk: true,
+		},
+		{
+			name:   "never",
+			spec:   "0 0 30 2 *",
+			after:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantOk: false,
+		},
+		{
+			name:  "time zone",
+			spec:  "CRON_TZ=Europe/Berlin 0 2 * * *",
+			after: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "daylight saving time starts",
+			spec:  "0 2 * * *",
+			after: time.Date(2024, 3, 30, 12, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2024, 4, 1, 2, 0, 0, 0, berlin),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "daylight saving time ends",
+			spec:  "0 2 * * *",
+			after: time.Date(2024, 10, 26, 12, 0, 0, 0, berlin),
+			want: []time.Time{
+				// First occurrence of 02:00, still in CEST
+				time.Date(2024, 10, 27, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 10, 28, 2, 0, 0, 0, berlin),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "daily across daylight saving time",
+			spec:  "0 3 * * *",
+			after: time.Date(2024, 3, 30, 12, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2024, 3, 31, 3, 0, 0, 0, berlin),
+				time.Date(2024, 4, 1, 3, 0, 0, 0, berlin),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "daylight saving time skips midnight",
+			spec:  "0 0 5 * * SAT",
+			after: time.Date(2024, 9, 7, 6, 0, 0, 0, santiago),
+			want: []time.Time{
+				time.Date(2024, 9, 14, 5, 0, 0, 0, santiago),
+			},
+			wantOk: true,
+		},
+		{
+			name:  "daily when midnight is skipped",
+			spec:  "0 30 0 * * *",
+			after: time.Date(2024, 9, 7, 12, 0, 0, 0, santiago),
+			want: []time.Time{
+				time.Date(2024, 9, 9, 0, 30, 0, 0, santiago),
+			},
+			wantOk: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule := MustParse(tt.spec)
+
+			after := tt.after
+			if !tt.wantOk {
+				_, ok := schedule.Next(after)
+				require.False(t, ok)
+				return
+			}
+
+			for _, want := range tt.want {
+				got, ok := schedule.Next(after)
+				require.True(t, ok)
+				require.True(t, want.Equal(got), "want %v, got %v", want, got)
+				require.Equal(t, tt.after.Location(), got.Location())
+
+				after = got
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	c "github.com/metamogul/timestone/v2/simulation/config"
 	"github.com/stretchr/testify/require"
 	"math/rand/v2"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -141,6 +142,7 @@ func TestApp(t *testing.T) {
 
 	testcases := []struct {
 		name               string
+		options            []simulation.Option
 		configureScheduler func(s *simulation.Scheduler)
 		result             string
 	}{
@@ -184,13 +186,69 @@ func TestApp(t *testing.T) {
 			},
 			result: "barbazfoo",
 		},
+		{
+			name: "foo after bar with selectors",
+			configureScheduler: func(s *simulation.Scheduler) {
+				s.ConfigureEvents(c.Config{
+					Selector: "foo*",
+					Priority: 3,
+					WaitFor: []c.Event{
+						c.All{Selector: "bar*"},
+					},
+				})
+				s.ConfigureEvents(c.Config{
+					Tags:     []string{"barProcessing"},
+					Priority: 1,
+					Adds: []*c.Generator{
+						{Tags: []string{"barPostprocessingBaz"}, Count: 5},
+					},
+				})
+				s.ConfigureEvents(c.Config{
+					Selector: "bar* && !barProcessing",
+					Priority: 2,
+				})
+			},
+			result: "barbazfoo",
+		},
+		{
+			name:    "foo before bar until idle",
+			options: []simulation.Option{simulation.RunUntilIdle()},
+			configureScheduler: func(s *simulation.Scheduler) {
+				s.ConfigureEvents(c.Config{
+					Tags: []string{"barProcessing"},
+					WaitFor: []c.Event{
+						c.All{Tags: []string{"fooProcessing"}},
+					},
+				})
+			},
+			result: "foobarbaz",
+		},
+		{
+			name:    "foo after bar until idle",
+			options: []simulation.Option{simulation.RunUntilIdle()},
+			configureScheduler: func(s *simulation.Scheduler) {
+				s.ConfigureEvents(c.Config{
+					Tags:     []string{"fooProcessing"},
+					Priority: 3,
+				})
+				s.ConfigureEvents(c.Config{
+					Tags:     []string{"barProcessing"},
+					Priority: 1,
+				})
+				s.ConfigureEvents(c.Config{
+					Tags:     []string{"barPostprocessingBaz"},
+					Priority: 2,
+				})
+			},
+			result: "barbazfoo",
+		},
 	}
 
 	for _, tt := range testcases {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			simulationScheduler := simulation.NewScheduler(now)
+			simulationScheduler := simulation.NewScheduler(now, tt.options...)
 
 			tt.configureScheduler(simulationScheduler)
 
@@ -206,3 +264,24 @@ func TestApp(t *testing.T) {
 		})
 	}
 }
+
+func TestApp_explore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	simulation.Explore(t, now, func(t *testing.T, s *simulation.Scheduler) {
+		a := newApp(s)
+		a.seedCache()
+		a.run()
+
+		s.Forward(1 * time.Hour)
+
+		for _, value := range a.cache.content {
+			require.Len(t, value, len("foobarbaz"))
+			require.Contains(t, value, "foo")
+			require.Contains(t, value, "bar")
+			require.Greater(t, strings.Index(value, "baz"), strings.Index(value, "bar"))
+		}
+	})
+}
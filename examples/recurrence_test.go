@@ -0,0 +1,74 @@
+package examples
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/iso8601"
+	"github.com/metamogul/timestone/v2/rrule"
+	"github.com/metamogul/timestone/v2/simulation"
+	"github.com/stretchr/testify/require"
+)
+
+type reporter struct {
+	reportTimes []time.Time
+	mu          sync.Mutex
+}
+
+func (r *reporter) report(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reportTimes = append(r.reportTimes, ctx.Value(timestone.ActionContextClockKey).(timestone.Clock).Now())
+}
+
+func TestReportOnFirstMondayOfTheMonth(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := &reporter{}
+
+	scheduler := simulation.NewScheduler(now)
+	scheduler.PerformScheduled(
+		context.Background(),
+		timestone.SimpleAction(r.report),
+		rrule.MustParse("FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1;BYHOUR=9;BYMINUTE=0;BYSECOND=0", now),
+		"report",
+	)
+
+	scheduler.Forward(24 * time.Hour * 91)
+
+	require.ElementsMatch(t, []time.Time{
+		time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+	}, r.reportTimes)
+}
+
+func TestReportHourlyFiveTimes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := &reporter{}
+
+	scheduler := simulation.NewScheduler(now.Add(-time.Second))
+	scheduler.PerformScheduled(
+		context.Background(),
+		timestone.SimpleAction(r.report),
+		iso8601.MustParse("R5/2024-01-01T00:00:00Z/PT1H"),
+		"report",
+	)
+
+	scheduler.Forward(24 * time.Hour)
+
+	require.ElementsMatch(t, []time.Time{
+		now,
+		now.Add(1 * time.Hour),
+		now.Add(2 * time.Hour),
+		now.Add(3 * time.Hour),
+		now.Add(4 * time.Hour),
+	}, r.reportTimes)
+}
@@ -235,7 +235,7 @@ func (w *timeWriter) run(ctx context.Context, writeInterval time.Duration) {
 	)
 }
 
-func ExampleNoRaceSelfWait() {
+func Example_noRaceSelfWait() {
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 	writeInterval := time.Minute
 
@@ -263,3 +263,33 @@ func ExampleNoRaceSelfWait() {
 	// 2024-01-01 12:05:00 +0000 UTC
 	// 2024-01-01 12:06:00 +0000 UTC
 }
+
+func TestNoRaceWriting_Expectations(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	writeInterval := time.Minute
+
+	scheduler := simulation.NewScheduler(now, simulation.RecordTrace())
+	scheduler.ConfigureEvents(
+		c.Config{
+			Tags:     []string{"writeTwo"},
+			Priority: 2,
+			WaitFor: []c.Event{c.Before{
+				Interval: 0,
+				Tags:     []string{"writeOne"},
+			}},
+		},
+	)
+
+	w := writer{scheduler: scheduler}
+	w.run(context.Background(), writeInterval)
+
+	scheduler.Forward(writeInterval)
+	simulation.ExpectOrder(t, scheduler, "writeOne", "writeTwo")
+
+	scheduler.Forward(2 * writeInterval)
+	simulation.ExpectRanAt(t, scheduler, "writeOne", now.Add(writeInterval), now.Add(2*writeInterval), now.Add(3*writeInterval))
+	simulation.ExpectCount(t, scheduler, "writeTwo", 3)
+	simulation.ExpectNotRan(t, scheduler, "writeThree")
+}
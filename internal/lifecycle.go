@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"context"
+	"sync"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// Lifecycle tracks the executions of a scheduled timestone.Action. It
+// implements all methods of timestone.Handle except Reset, which is up
+// to the respective scheduler.
+type Lifecycle struct {
+	ctx context.Context
+
+	running   int
+	runs      int
+	exhausted bool
+	cancelled bool
+
+	onCancelled func()
+	// stop unregisters the cancellation by ctx once the Lifecycle is done,
+	// so that long-lived contexts don't accumulate registrations.
+	stop func() bool
+
+	done chan struct{}
+	mu   sync.Mutex
+}
+
+// NewLifecycle returns a new Lifecycle for an Action scheduled with ctx.
+// Cancelling ctx cancels the Lifecycle as well.
+func NewLifecycle(ctx context.Context) *Lifecycle {
+	return NewObservedLifecycle(ctx, nil)
+}
+
+// NewObservedLifecycle returns a new Lifecycle like NewLifecycle, which
+// calls onCancelled once it has been cancelled before being done. The
+// callback is optional.
+func NewObservedLifecycle(ctx context.Context, onCancelled func()) *Lifecycle {
+	l := &Lifecycle{
+		ctx:         ctx,
+		onCancelled: onCancelled,
+		done:        make(chan struct{}),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stop = context.AfterFunc(ctx, l.Cancel)
+
+	return l
+}
+
+func (l *Lifecycle) Cancel() {
+	l.mu.Lock()
+	notify := !l.cancelled && !l.isDone() && l.onCancelled != nil
+	l.cancelled = true
+	l.exhausted = true
+	l.closeIfDone()
+	l.mu.Unlock()
+
+	if notify {
+		l.onCancelled()
+	}
+}
+
+// Cancelled reports whether the Lifecycle has been cancelled, either via
+// Cancel or its context.Context.
+func (l *Lifecycle) Cancelled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.isCancelled()
+}
+
+// Start records that an execution has started. It returns false if the
+// Lifecycle has been cancelled, in which case the execution must not be
+// performed.
+func (l *Lifecycle) Start() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.isCancelled() {
+		return false
+	}
+
+	l.running++
+	l.runs++
+
+	return true
+}
+
+// Finish records that an execution started via Start has returned.
+func (l *Lifecycle) Finish() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.running--
+	l.closeIfDone()
+}
+
+// Exhaust records that no further executions are scheduled.
+func (l *Lifecycle) Exhaust() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.exhausted = true
+	l.closeIfDone()
+}
+
+// Revive reverts Exhaust in order to schedule further executions. It
+// returns false if the Lifecycle is already done or cancelled.
+func (l *Lifecycle) Revive() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.isDone() || l.isCancelled() {
+		return false
+	}
+
+	l.exhausted = false
+
+	return true
+}
+
+func (l *Lifecycle) Done() <-chan struct{} {
+	return l.done
+}
+
+func (l *Lifecycle) Status() timestone.Status {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case l.isCancelled():
+		return timestone.StatusCancelled
+	case l.running > 0:
+		return timestone.StatusRunning
+	case l.exhausted:
+		return timestone.StatusFinished
+	default:
+		return timestone.StatusScheduled
+	}
+}
+
+func (l *Lifecycle) Runs() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.runs
+}
+
+func (l *Lifecycle) closeIfDone() {
+	if l.exhausted && l.running == 0 && !l.isDone() {
+		close(l.done)
+		l.stop()
+	}
+}
+
+func (l *Lifecycle) isCancelled() bool {
+	return l.cancelled || l.ctx.Err() != nil
+}
+
+func (l *Lifecycle) isDone() bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+		return false
+	}
+}
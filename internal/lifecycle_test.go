@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func requireDone(t *testing.T, l *Lifecycle, done bool) {
+	t.Helper()
+
+	select {
+	case <-l.Done():
+		require.True(t, done, "lifecycle is done")
+	default:
+		require.False(t, done, "lifecycle is not done")
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	t.Parallel()
+
+	l := NewLifecycle(context.Background())
+	require.Equal(t, timestone.StatusScheduled, l.Status())
+
+	require.True(t, l.Start())
+	require.Equal(t, timestone.StatusRunning, l.Status())
+
+	l.Exhaust()
+	requireDone(t, l, false)
+
+	l.Finish()
+	require.Equal(t, timestone.StatusFinished, l.Status())
+	require.Equal(t, 1, l.Runs())
+	requireDone(t, l, true)
+
+	require.False(t, l.Revive())
+}
+
+func TestLifecycle_Revive(t *testing.T) {
+	t.Parallel()
+
+	l := NewLifecycle(context.Background())
+
+	require.True(t, l.Start())
+	l.Exhaust()
+
+	require.True(t, l.Revive())
+	l.Finish()
+	require.Equal(t, timestone.StatusScheduled, l.Status())
+	requireDone(t, l, false)
+}
+
+func TestLifecycle_Cancel(t *testing.T) {
+	t.Parallel()
+
+	l := NewLifecycle(context.Background())
+
+	require.True(t, l.Start())
+	l.Cancel()
+	require.Equal(t, timestone.StatusCancelled, l.Status())
+	require.True(t, l.Cancelled())
+	requireDone(t, l, false)
+
+	l.Finish()
+	requireDone(t, l, true)
+
+	require.False(t, l.Start())
+	require.False(t, l.Revive())
+	require.Equal(t, 1, l.Runs())
+}
+
+func TestLifecycle_contextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := NewLifecycle(ctx)
+
+	cancel()
+
+	require.True(t, l.Cancelled())
+	require.False(t, l.Start())
+	<-l.Done()
+}
+
+func TestNewObservedLifecycle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		lifecycle func(l *Lifecycle, cancel context.CancelFunc)
+		want      int
+	}{
+		{
+			name: "cancelled",
+			lifecycle: func(l *Lifecycle, _ context.CancelFunc) {
+				l.Cancel()
+				l.Cancel()
+			},
+			want: 1,
+		},
+		{
+			name: "context cancelled",
+			lifecycle: func(l *Lifecycle, cancel context.CancelFunc) {
+				cancel()
+				<-l.Done()
+			},
+			want: 1,
+		},
+		{
+			name: "cancelled when done",
+			lifecycle: func(l *Lifecycle, _ context.CancelFunc) {
+				l.Exhaust()
+				l.Cancel()
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cancellations := make(chan struct{}, 2)
+			l := NewObservedLifecycle(ctx, func() { cancellations <- struct{}{} })
+
+			tt.lifecycle(l, cancel)
+
+			for range tt.want {
+				<-cancellations
+			}
+			require.Empty(t, cancellations)
+		})
+	}
+}
+
+// afterFuncContext counts the functions registered by context.AfterFunc
+// that haven't been stopped. It needs its own Done channel for
+// context.AfterFunc to use its AfterFunc method.
+type afterFuncContext struct {
+	context.Context
+	done       chan struct{}
+	registered atomic.Int32
+}
+
+func (c *afterFuncContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *afterFuncContext) AfterFunc(func()) (stop func() bool) {
+	c.registered.Add(1)
+
+	return func() bool {
+		c.registered.Add(-1)
+		return true
+	}
+}
+
+func TestLifecycle_stopsAfterFunc(t *testing.T) {
+	t.Parallel()
+
+	ctx := &afterFuncContext{Context: context.Background(), done: make(chan struct{})}
+
+	l := NewLifecycle(ctx)
+	require.EqualValues(t, 1, ctx.registered.Load())
+
+	require.True(t, l.Start())
+	l.Exhaust()
+	require.EqualValues(t, 1, ctx.registered.Load())
+
+	l.Finish()
+	requireDone(t, l, true)
+	require.EqualValues(t, 0, ctx.registered.Load())
+}
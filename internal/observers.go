@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// Observers notifies the timestone.Observer s attached to a scheduler.
+// Its zero value is ready to use, and a nil Observers notifies nobody.
+type Observers struct {
+	observers []timestone.Observer
+	mu        sync.RWMutex
+}
+
+// Add attaches observer.
+func (o *Observers) Add(observer timestone.Observer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.observers = append(o.observers, observer)
+}
+
+func (o *Observers) Scheduled(tags []string, at time.Time) {
+	o.notify(func(observer timestone.Observer) { observer.OnScheduled(tags, at) })
+}
+
+func (o *Observers) Released(tags []string, at time.Time) {
+	o.notify(func(observer timestone.Observer) { observer.OnReleased(tags, at) })
+}
+
+func (o *Observers) Started(tags []string, at time.Time) {
+	o.notify(func(observer timestone.Observer) { observer.OnStarted(tags, at) })
+}
+
+func (o *Observers) Finished(tags []string, at time.Time) {
+	o.notify(func(observer timestone.Observer) { observer.OnFinished(tags, at) })
+}
+
+func (o *Observers) Cancelled(tags []string, at time.Time) {
+	o.notify(func(observer timestone.Observer) { observer.OnCancelled(tags, at) })
+}
+
+// Recover notifies the observers about a panic of the execution of an
+// Action, and panics again with the same value. It must be deferred
+// before performing the Action. Without observers, the panic isn't
+// recovered at all, so that its original stack is kept.
+func (o *Observers) Recover(tags []string, clock timestone.Clock) {
+	if len(o.all()) == 0 {
+		return
+	}
+
+	if value := recover(); value != nil {
+		at := clock.Now()
+		o.notify(func(observer timestone.Observer) { observer.OnPanicked(tags, at, value) })
+		panic(value)
+	}
+}
+
+func (o *Observers) notify(f func(observer timestone.Observer)) {
+	for _, observer := range o.all() {
+		f(observer)
+	}
+}
+
+func (o *Observers) all() []timestone.Observer {
+	if o == nil {
+		return nil
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return slices.Clone(o.observers)
+}
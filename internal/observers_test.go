@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type panicObserver struct {
+	timestone.NopObserver
+	values []any
+}
+
+func (o *panicObserver) OnPanicked(_ []string, _ time.Time, value any) {
+	o.values = append(o.values, value)
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestObservers_Recover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		observers func(observer *panicObserver) *Observers
+		action    func()
+		want      []any
+		wantPanic bool
+	}{
+		{
+			name: "panic",
+			observers: func(observer *panicObserver) *Observers {
+				o := &Observers{}
+				o.Add(observer)
+				return o
+			},
+			action:    func() { panic("boom") },
+			want:      []any{"boom"},
+			wantPanic: true,
+		},
+		{
+			name: "no panic",
+			observers: func(observer *panicObserver) *Observers {
+				o := &Observers{}
+				o.Add(observer)
+				return o
+			},
+			action: func() {},
+		},
+		{
+			name:      "no observers",
+			observers: func(*panicObserver) *Observers { return nil },
+			action:    func() { panic("boom") },
+			wantPanic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			observer := &panicObserver{}
+			observers := tt.observers(observer)
+
+			perform := func() {
+				defer observers.Recover([]string{"tag"}, fixedClock(time.Time{}))
+				tt.action()
+			}
+
+			if tt.wantPanic {
+				require.PanicsWithValue(t, "boom", perform)
+			} else {
+				require.NotPanics(t, perform)
+			}
+			require.Equal(t, tt.want, observer.values)
+		})
+	}
+}
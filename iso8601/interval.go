@@ -0,0 +1,117 @@
+package iso8601
+
+import (
+	"math"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// maxRepetitions bounds the search for the next recurrence of unbounded
+// intervals.
+const maxRepetitions = math.MaxInt32
+
+var _ timestone.Schedule = (*RepeatingInterval)(nil)
+
+// duration is a nominal ISO 8601 duration. Calendar components are added
+// to the date, so that e.g. "P1M" recurs on the same day of every month,
+// or on the last day of months that are too short, and "P1D" at the same
+// wall clock time across daylight saving time transitions.
+type duration struct {
+	years, months, days int
+	clock               time.Duration
+}
+
+func (d duration) isZero() bool {
+	return d.years == 0 && d.months == 0 && d.days == 0 && d.clock == 0
+}
+
+// addTo adds the duration n times to t. Unlike time.Time.AddDate, years
+// and months that would overflow the day of the month are clamped to its
+// last day.
+func (d duration) addTo(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+
+	// Normalize the target month by the first day, which always exists.
+	first := time.Date(year+n*d.years, month+time.Month(n*d.months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	t = time.Date(first.Year(), first.Month(), min(day, lastDay), hour, minute, second, t.Nanosecond(), t.Location())
+
+	// Add days and clock time in chunks, so that n times either of them
+	// doesn't overflow.
+	days, clock := d.days, d.clock
+	if n < 0 {
+		n, days, clock = -n, -days, -clock
+	}
+	for remaining := n; remaining > 0; {
+		chunk := min(remaining, math.MaxInt/max(abs(days), 1))
+		t = t.AddDate(0, 0, chunk*days)
+		remaining -= chunk
+	}
+	for remaining := n; remaining > 0; {
+		chunk := min(time.Duration(remaining), math.MaxInt64/max(abs(clock), 1))
+		t = t.Add(chunk * clock)
+		remaining -= int(chunk)
+	}
+
+	return t
+}
+
+func abs[T int | time.Duration](x T) T {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+// RepeatingInterval is a parsed ISO 8601 repeating interval. Use Parse,
+// ParseInLocation or MustParse to create one. It implements
+// timestone.Schedule, recurring at the start of each repetition.
+type RepeatingInterval struct {
+	// repetitions is negative for unbounded intervals.
+	repetitions int
+	start       time.Time
+	period      duration
+}
+
+// Next returns the start of the first repetition strictly after after.
+// The second return value is false if the RepeatingInterval doesn't
+// recur anymore.
+func (r *RepeatingInterval) Next(after time.Time) (time.Time, bool) {
+	limit := r.repetitions
+	if limit < 0 {
+		limit = maxRepetitions
+	}
+
+	// Repetitions are computed from the start instead of one another, so
+	// that e.g. "P1M" starting on the 31st doesn't drift. Search for the
+	// first repetition after after, exponentially first and then binary.
+	high := 1
+	for high < limit && !r.repetition(high-1).After(after) {
+		// Double high without overflowing limit.
+		high += min(high, limit-high)
+	}
+
+	low := 0
+	for low < high {
+		middle := low + (high-low)/2
+		if r.repetition(middle).After(after) {
+			high = middle
+		} else {
+			low = middle + 1
+		}
+	}
+
+	if low >= limit {
+		return time.Time{}, false
+	}
+
+	return r.repetition(low), true
+}
+
+func (r *RepeatingInterval) repetition(n int) time.Time {
+	return r.period.addTo(r.start, n)
+}
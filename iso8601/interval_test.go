@@ -0,0 +1,126 @@
+package iso8601
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepeatingInterval_Next(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		interval *RepeatingInterval
+		after    time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "bounded",
+			interval: MustParse("R3/2024-01-01T00:00:00Z/PT1H"),
+			after:    time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "unbounded after start",
+			interval: MustParse("R/2024-01-01T00:00:00Z/PT1H"),
+			after:    time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC),
+				time.Date(2024, 6, 1, 14, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "months are clamped without drifting",
+			interval: MustParse("R/2024-01-31T00:00:00Z/P1M"),
+			after:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "days keep the wall clock time",
+			interval: func() *RepeatingInterval {
+				interval, err := ParseInLocation("R/2024-03-30T09:00:00/P1D", berlin)
+				require.NoError(t, err)
+				return interval
+			}(),
+			after: time.Date(2024, 3, 30, 9, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2024, 3, 31, 9, 0, 0, 0, berlin),
+				time.Date(2024, 4, 1, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "duration and end",
+			interval: MustParse("R2/PT1H/2024-01-01T02:00:00Z"),
+			after:    time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "no repetitions",
+			interval: MustParse("R0/2024-01-01T00:00:00Z/PT1H"),
+			after:    time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			want:     []time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := make([]time.Time, 0, len(tt.want))
+			after := tt.after
+			for {
+				next, ok := tt.interval.Next(after)
+				if !ok || len(got) == len(tt.want) {
+					break
+				}
+
+				got = append(got, next)
+				after = next
+			}
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRepeatingInterval_Next_finished(t *testing.T) {
+	t.Parallel()
+
+	interval := MustParse("R3/2024-01-01T00:00:00Z/PT1H")
+
+	_, ok := interval.Next(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC))
+	require.False(t, ok)
+}
+
+func TestRepeatingInterval_Next_nearOverflow(t *testing.T) {
+	t.Parallel()
+
+	// Two repetitions of the longest possible clock time overflow a
+	// time.Duration.
+	interval := MustParse("R3/2024-01-01T00:00:00Z/PT2562047H")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	period := 2562047 * time.Hour
+
+	next, ok := interval.Next(start.Add(period))
+	require.True(t, ok)
+	require.Equal(t, start.Add(period).Add(period), next)
+
+	_, ok = interval.Next(next)
+	require.False(t, ok)
+}
@@ -0,0 +1,216 @@
+package iso8601
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidInterval = errors.New("invalid repeating interval")
+
+var dateTimeLayouts = []string{
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"20060102T150405Z0700",
+	"20060102T150405",
+	"20060102",
+}
+
+// Parse parses an ISO 8601 repeating interval like
+// "R5/2024-01-01T00:00:00Z/PT1H" into a RepeatingInterval. Date-times
+// without a time zone are interpreted as UTC.
+//
+// Supported forms are "Rn/start/duration", "Rn/start/end" and
+// "Rn/duration/end". The number of repetitions n may be omitted for an
+// unbounded interval, except for the last form which needs a start.
+func Parse(text string) (*RepeatingInterval, error) {
+	return ParseInLocation(text, time.UTC)
+}
+
+// ParseInLocation is like Parse but interprets date-times without a time
+// zone in location.
+func ParseInLocation(text string, location *time.Location) (*RepeatingInterval, error) {
+	parts := strings.Split(strings.TrimSpace(text), "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected three parts in %q", ErrInvalidInterval, text)
+	}
+
+	repetitions, err := parseRepetitions(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	interval := &RepeatingInterval{repetitions: repetitions}
+
+	switch {
+	case strings.HasPrefix(parts[1], "P"):
+		if repetitions < 0 {
+			return nil, fmt.Errorf("%w: unbounded interval %q has no start", ErrInvalidInterval, text)
+		}
+
+		if interval.period, err = parseDuration(parts[1]); err != nil {
+			return nil, err
+		}
+
+		end, err := parseDateTime(parts[2], location)
+		if err != nil {
+			return nil, err
+		}
+		interval.start = interval.period.addTo(end, -repetitions)
+	case strings.HasPrefix(parts[2], "P"):
+		if interval.start, err = parseDateTime(parts[1], location); err != nil {
+			return nil, err
+		}
+
+		if interval.period, err = parseDuration(parts[2]); err != nil {
+			return nil, err
+		}
+	default:
+		if interval.start, err = parseDateTime(parts[1], location); err != nil {
+			return nil, err
+		}
+
+		end, err := parseDateTime(parts[2], location)
+		if err != nil {
+			return nil, err
+		}
+
+		if !end.After(interval.start) {
+			return nil, fmt.Errorf("%w: end %q is not after start %q", ErrInvalidInterval, parts[2], parts[1])
+		}
+		interval.period = duration{clock: end.Sub(interval.start)}
+	}
+
+	return interval, nil
+}
+
+// MustParse is like Parse but panics if text can't be parsed.
+func MustParse(text string) *RepeatingInterval {
+	interval, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+
+	return interval
+}
+
+func parseRepetitions(value string) (int, error) {
+	if !strings.HasPrefix(value, "R") {
+		return 0, fmt.Errorf("%w: %q doesn't start with R", ErrInvalidInterval, value)
+	}
+
+	value = strings.TrimPrefix(value, "R")
+	if value == "" || value == "-1" {
+		return -1, nil
+	}
+
+	repetitions, err := strconv.Atoi(value)
+	if err != nil || repetitions < 0 {
+		return 0, fmt.Errorf("%w: invalid number of repetitions %q", ErrInvalidInterval, value)
+	}
+
+	return repetitions, nil
+}
+
+func parseDateTime(value string, location *time.Location) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: invalid date-time %q", ErrInvalidInterval, value)
+}
+
+// parseDuration parses durations like "P1Y2M3DT4H5M6.5S" or "P2W". Only
+// the last component may have a fraction, and only if it is a time
+// component.
+func parseDuration(value string) (duration, error) {
+	invalid := fmt.Errorf("%w: invalid duration %q", ErrInvalidInterval, value)
+
+	rest, found := strings.CutPrefix(value, "P")
+	if !found || rest == "" || strings.HasSuffix(rest, "T") {
+		return duration{}, invalid
+	}
+
+	var (
+		result      duration
+		inTime      bool
+		designators = "YMWD"
+		hasFraction bool
+	)
+
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime {
+				return duration{}, invalid
+			}
+			inTime, designators, rest = true, "HMS", rest[1:]
+			continue
+		}
+
+		end := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if end <= 0 || hasFraction {
+			return duration{}, invalid
+		}
+
+		number, designator := strings.ReplaceAll(rest[:end], ",", "."), rest[end]
+		rest = rest[end+1:]
+
+		// Designators must occur at most once and in order.
+		position := strings.IndexByte(designators, designator)
+		if position < 0 {
+			return duration{}, invalid
+		}
+		designators = designators[position+1:]
+
+		if strings.Contains(number, ".") {
+			if !inTime {
+				return duration{}, invalid
+			}
+			hasFraction = true
+		}
+
+		amount, err := strconv.ParseFloat(number, 64)
+		if err != nil || amount > math.MaxInt32 {
+			return duration{}, invalid
+		}
+
+		switch {
+		case !inTime && designator == 'Y':
+			result.years = int(amount)
+		case !inTime && designator == 'M':
+			result.months = int(amount)
+		case !inTime && designator == 'W':
+			result.days += 7 * int(amount)
+		case !inTime && designator == 'D':
+			result.days += int(amount)
+		case designator == 'H':
+			amount *= float64(time.Hour)
+		case designator == 'M':
+			amount *= float64(time.Minute)
+		case designator == 'S':
+			amount *= float64(time.Second)
+		}
+
+		if inTime {
+			// The clock time must fit into a time.Duration.
+			if amount >= float64(math.MaxInt64-result.clock) {
+				return duration{}, invalid
+			}
+			result.clock += time.Duration(amount)
+		}
+	}
+
+	if result.isZero() {
+		return duration{}, fmt.Errorf("%w: zero duration %q", ErrInvalidInterval, value)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package iso8601
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		text       string
+		want       *RepeatingInterval
+		requireErr bool
+	}{
+		{
+			name: "start and duration",
+			text: "R5/2024-01-01T00:00:00Z/PT1H",
+			want: &RepeatingInterval{repetitions: 5, start: start, period: duration{clock: time.Hour}},
+		},
+		{
+			name: "unbounded",
+			text: "R/2024-01-01T00:00:00Z/P1Y2M3DT4H5M6.5S",
+			want: &RepeatingInterval{
+				repetitions: -1,
+				start:       start,
+				period: duration{
+					years:  1,
+					months: 2,
+					days:   3,
+					clock:  4*time.Hour + 5*time.Minute + 6500*time.Millisecond,
+				},
+			},
+		},
+		{
+			name: "weeks",
+			text: "R2/2024-01-01/P2W",
+			want: &RepeatingInterval{repetitions: 2, start: start, period: duration{days: 14}},
+		},
+		{
+			name: "start and end",
+			text: "R3/20240101T000000Z/20240101T003000Z",
+			want: &RepeatingInterval{repetitions: 3, start: start, period: duration{clock: 30 * time.Minute}},
+		},
+		{
+			name: "duration and end",
+			text: "R3/PT1,5H/2024-01-01T04:30:00Z",
+			want: &RepeatingInterval{repetitions: 3, start: start, period: duration{clock: 90 * time.Minute}},
+		},
+		{
+			name: "offset",
+			text: "R1/2024-01-01T01:00:00+01:00/P1D",
+			want: &RepeatingInterval{
+				repetitions: 1,
+				start:       time.Date(2024, 1, 1, 1, 0, 0, 0, time.FixedZone("", 3600)),
+				period:      duration{days: 1},
+			},
+		},
+		{name: "missing repetitions", text: "2024-01-01T00:00:00Z/PT1H", requireErr: true},
+		{name: "too many parts", text: "R5/2024-01-01T00:00:00Z/PT1H/PT1H", requireErr: true},
+		{name: "negative repetitions", text: "R-2/2024-01-01T00:00:00Z/PT1H", requireErr: true},
+		{name: "unbounded without start", text: "R/PT1H/2024-01-01T00:00:00Z", requireErr: true},
+		{name: "invalid start", text: "R5/yesterday/PT1H", requireErr: true},
+		{name: "end before start", text: "R5/2024-01-02/2024-01-01", requireErr: true},
+		{name: "empty duration", text: "R5/2024-01-01/P", requireErr: true},
+		{name: "empty time part", text: "R5/2024-01-01/P1DT", requireErr: true},
+		{name: "zero duration", text: "R5/2024-01-01/PT0S", requireErr: true},
+		{name: "unordered duration", text: "R5/2024-01-01/PT1S1H", requireErr: true},
+		{name: "fractional day", text: "R5/2024-01-01/P1.5D", requireErr: true},
+		{name: "fraction not last", text: "R5/2024-01-01/PT1.5H30M", requireErr: true},
+		{name: "unknown designator", text: "R5/2024-01-01/P1X", requireErr: true},
+		{name: "clock time overflowing", text: "R5/2024-01-01/PT2562048H", requireErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.text)
+			if tt.requireErr {
+				require.ErrorIs(t, err, ErrInvalidInterval)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, tt.want.start.Equal(got.start))
+			require.Equal(t, tt.want.repetitions, got.repetitions)
+			require.Equal(t, tt.want.period, got.period)
+		})
+	}
+}
+
+func TestParseInLocation(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	interval, err := ParseInLocation("R/2024-01-01T09:00:00/P1D", berlin)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 1, 9, 0, 0, 0, berlin), interval.start)
+}
+
+func TestMustParse(t *testing.T) {
+	t.Parallel()
+
+	require.NotPanics(t, func() { MustParse("R/2024-01-01/P1D") })
+	require.Panics(t, func() { MustParse("R/2024-01-01") })
+}
@@ -2,6 +2,7 @@ package timestone
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -35,6 +36,73 @@ func (s SimpleAction) Perform(ctx context.Context) {
 	s(ctx)
 }
 
+// Schedule describes the points in time at which a recurring Action is
+// to be performed. The package schedule provides common implementations
+// and combinators, while a *cron.Schedule implements Schedule for cron
+// specs.
+type Schedule interface {
+	// Next returns the earliest point in time of the Schedule strictly
+	// after after. The second return value is false if there is none.
+	Next(after time.Time) (time.Time, bool)
+}
+
+// Status describes the state of an Action scheduled by a Scheduler, as
+// reported by its Handle.
+type Status int
+
+const (
+	// StatusScheduled means that the Action is waiting to be performed.
+	StatusScheduled Status = iota
+	// StatusRunning means that the Action is being performed.
+	StatusRunning
+	// StatusFinished means that all executions of the Action have taken
+	// place and none is left to be performed.
+	StatusFinished
+	// StatusCancelled means that the Action won't be performed anymore
+	// because either its Handle or its context.Context was cancelled.
+	StatusCancelled
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusScheduled:
+		return "scheduled"
+	case StatusRunning:
+		return "running"
+	case StatusFinished:
+		return "finished"
+	case StatusCancelled:
+		return "cancelled"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Handle controls an Action scheduled through one of the
+// Perform...WithHandle methods of a HandleScheduler. It removes the need
+// to create a dedicated context.Context just to be able to stop a
+// scheduled Action.
+type Handle interface {
+	// Cancel prevents all further executions of the Action. Executions
+	// that are already running are not interrupted.
+	Cancel()
+	// Reset reschedules the next execution of the Action to take place
+	// after duration, measured from the current time of the Scheduler's
+	// clock. For Action s performed repeatedly, duration also becomes the
+	// new interval, while Action s performed on a Schedule continue to
+	// follow it after the rescheduled execution. Reset returns false and
+	// has no effect if the Handle is already done.
+	Reset(duration time.Duration) bool
+	// Done returns a channel that is closed once the Action won't be
+	// performed anymore and all of its executions have returned.
+	Done() <-chan struct{}
+	// Status returns the current Status of the Action.
+	Status() Status
+	// Runs returns the number of executions of the Action that have been
+	// started so far.
+	Runs() int
+}
+
 // Scheduler encapsulates the scheduling of Action s and should replace
 // every use of goroutines to enable deterministic unit tests.
 //
@@ -62,4 +130,31 @@ type Scheduler interface {
 	// after an initial delay of interval. If until is provided, the last
 	// event will be run before or at until.
 	PerformRepeatedly(ctx context.Context, action Action, until *time.Time, interval time.Duration, tags ...string)
+	// PerformOnSchedule schedules an action to be run at every firing of
+	// the cron spec schedule, as understood by cron.Parse. An error is
+	// returned if schedule can't be parsed.
+	PerformOnSchedule(ctx context.Context, action Action, schedule string, tags ...string) error
+	// PerformScheduled schedules an action to be run at every point in
+	// time of schedule after the current time of the Scheduler's clock.
+	PerformScheduled(ctx context.Context, action Action, schedule Schedule, tags ...string)
+}
+
+// HandleScheduler is a Scheduler that additionally returns a Handle to
+// control the Action s it schedules. Both the system.Scheduler and the
+// simulation.Scheduler implement it.
+type HandleScheduler interface {
+	Scheduler
+	// PerformNowWithHandle is like PerformNow, but returns a Handle.
+	PerformNowWithHandle(ctx context.Context, action Action, tags ...string) Handle
+	// PerformAfterWithHandle is like PerformAfter, but returns a Handle.
+	PerformAfterWithHandle(ctx context.Context, action Action, duration time.Duration, tags ...string) Handle
+	// PerformRepeatedlyWithHandle is like PerformRepeatedly, but returns
+	// a Handle.
+	PerformRepeatedlyWithHandle(ctx context.Context, action Action, until *time.Time, interval time.Duration, tags ...string) Handle
+	// PerformOnScheduleWithHandle is like PerformOnSchedule, but returns
+	// a Handle.
+	PerformOnScheduleWithHandle(ctx context.Context, action Action, schedule string, tags ...string) (Handle, error)
+	// PerformScheduledWithHandle is like PerformScheduled, but returns a
+	// Handle.
+	PerformScheduledWithHandle(ctx context.Context, action Action, schedule Schedule, tags ...string) Handle
 }
@@ -0,0 +1,41 @@
+package timestone
+
+import "time"
+
+// Observer is notified about the lifecycle of the Action s scheduled by a
+// Scheduler it has been attached to, e.g. to log them, record metrics or
+// make assertions in tests without wrapping every Action. Both the
+// system.Scheduler and the simulation.Scheduler notify it, measuring
+// time by their respective clock. The methods receive the tags the Action
+// has been scheduled with, and might be called concurrently.
+type Observer interface {
+	// OnScheduled is called when an execution of an Action has been
+	// scheduled for at.
+	OnScheduled(tags []string, at time.Time)
+	// OnReleased is called at at when an execution of an Action is due,
+	// before it starts.
+	OnReleased(tags []string, at time.Time)
+	// OnStarted is called at at when an execution of an Action starts.
+	OnStarted(tags []string, at time.Time)
+	// OnFinished is called at at when an execution of an Action has
+	// returned.
+	OnFinished(tags []string, at time.Time)
+	// OnPanicked is called at at when an execution of an Action has
+	// panicked with value, before the panic is propagated.
+	OnPanicked(tags []string, at time.Time, value any)
+	// OnCancelled is called at at when an Action has been cancelled via
+	// its Handle or context.Context before all of its executions have
+	// taken place.
+	OnCancelled(tags []string, at time.Time)
+}
+
+// NopObserver implements Observer without doing anything. Embed it in
+// an Observer that is only interested in some of the notifications.
+type NopObserver struct{}
+
+func (NopObserver) OnScheduled([]string, time.Time)     {}
+func (NopObserver) OnReleased([]string, time.Time)      {}
+func (NopObserver) OnStarted([]string, time.Time)       {}
+func (NopObserver) OnFinished([]string, time.Time)      {}
+func (NopObserver) OnPanicked([]string, time.Time, any) {}
+func (NopObserver) OnCancelled([]string, time.Time)     {}
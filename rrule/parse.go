@@ -0,0 +1,270 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var frequencies = map[string]Frequency{
+	"SECONDLY": Secondly,
+	"MINUTELY": Minutely,
+	"HOURLY":   Hourly,
+	"DAILY":    Daily,
+	"WEEKLY":   Weekly,
+	"MONTHLY":  Monthly,
+	"YEARLY":   Yearly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Parse parses an RFC 5545 recurrence rule like
+// "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1" into a Rule, optionally prefixed by
+// "RRULE:". The first occurrence is derived from dtstart.
+//
+// Alternatively text may consist of a "DTSTART" and an "RRULE" line as
+// found in iCalendar data, e.g.
+//
+//	DTSTART;TZID=Europe/Berlin:20240101T090000
+//	RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR
+//
+// in which case dtstart is ignored. Date-times without a time zone are
+// interpreted in the location of dtstart.
+//
+// Supported rule parts are FREQ, INTERVAL, COUNT, UNTIL, BYSECOND,
+// BYMINUTE, BYHOUR, BYDAY, BYMONTHDAY, BYYEARDAY, BYMONTH, BYSETPOS and
+// WKST.
+func Parse(text string, dtstart time.Time) (*Rule, error) {
+	var ruleValue string
+
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "DTSTART"):
+			parsedStart, err := parseStart(line, dtstart.Location())
+			if err != nil {
+				return nil, err
+			}
+			dtstart = parsedStart
+		case strings.HasPrefix(line, "RRULE:"):
+			ruleValue = strings.TrimPrefix(line, "RRULE:")
+		case line != "":
+			ruleValue = line
+		}
+	}
+
+	if ruleValue == "" {
+		return nil, fmt.Errorf("%w: no rule in %q", ErrInvalidRule, text)
+	}
+
+	rule, err := parseRule(ruleValue, dtstart.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	rule.start = dtstart
+	rule.applyDefaults()
+
+	return rule, nil
+}
+
+// MustParse is like Parse but panics if text can't be parsed.
+func MustParse(text string, dtstart time.Time) *Rule {
+	rule, err := Parse(text, dtstart)
+	if err != nil {
+		panic(err)
+	}
+
+	return rule
+}
+
+func parseStart(line string, location *time.Location) (time.Time, error) {
+	name, value, found := strings.Cut(line, ":")
+	if !found {
+		return time.Time{}, fmt.Errorf("%w: invalid DTSTART %q", ErrInvalidRule, line)
+	}
+
+	for _, parameter := range strings.Split(name, ";")[1:] {
+		key, parameterValue, _ := strings.Cut(parameter, "=")
+		if key != "TZID" {
+			continue
+		}
+
+		loc, err := time.LoadLocation(parameterValue)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: unknown time zone %q: %v", ErrInvalidRule, parameterValue, err)
+		}
+		location = loc
+	}
+
+	return parseDateTime(value, location)
+}
+
+func parseDateTime(value string, location *time.Location) (time.Time, error) {
+	if strings.HasSuffix(value, "Z") {
+		value = strings.TrimSuffix(value, "Z")
+		location = time.UTC
+	}
+
+	for _, layout := range []string{"20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: invalid date-time %q", ErrInvalidRule, value)
+}
+
+func parseRule(value string, location *time.Location) (*Rule, error) {
+	rule := &Rule{
+		interval:  1,
+		weekStart: time.Monday,
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, partValue, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: invalid rule part %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			frequency, ok := frequencies[strings.ToUpper(partValue)]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown frequency %q", ErrInvalidRule, partValue)
+			}
+			rule.frequency = frequency
+		case "INTERVAL":
+			rule.interval, err = parseNumber(key, partValue, 1, 0)
+		case "COUNT":
+			rule.count, err = parseNumber(key, partValue, 1, 0)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseDateTime(partValue, location)
+			rule.until = &until
+		case "BYSECOND":
+			rule.bySecond, err = parseNumbers(key, partValue, 0, 59, false)
+		case "BYMINUTE":
+			rule.byMinute, err = parseNumbers(key, partValue, 0, 59, false)
+		case "BYHOUR":
+			rule.byHour, err = parseNumbers(key, partValue, 0, 23, false)
+		case "BYDAY":
+			rule.byDay, err = parseWeekdays(partValue)
+		case "BYMONTHDAY":
+			rule.byMonthDay, err = parseNumbers(key, partValue, 1, 31, true)
+		case "BYYEARDAY":
+			rule.byYearDay, err = parseNumbers(key, partValue, 1, 366, true)
+		case "BYMONTH":
+			rule.byMonth, err = parseNumbers(key, partValue, 1, 12, false)
+		case "BYSETPOS":
+			rule.bySetPos, err = parseNumbers(key, partValue, 1, 366, true)
+		case "WKST":
+			weekStart, ok := weekdays[strings.ToUpper(partValue)]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown week day %q", ErrInvalidRule, partValue)
+			}
+			rule.weekStart = weekStart
+		default:
+			return nil, fmt.Errorf("%w: unsupported rule part %q", ErrInvalidRule, key)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.frequency == 0 {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+
+	if rule.count > 0 && rule.until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL must not occur together", ErrInvalidRule)
+	}
+
+	for _, day := range rule.byDay {
+		if day.n != 0 && rule.frequency != Monthly && rule.frequency != Yearly {
+			return nil, fmt.Errorf("%w: numbered BYDAY requires FREQ=MONTHLY or FREQ=YEARLY", ErrInvalidRule)
+		}
+	}
+
+	return rule, nil
+}
+
+func parseNumber(key, value string, min, max int) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < min || (max > 0 && number > max) {
+		return 0, fmt.Errorf("%w: invalid %s value %q", ErrInvalidRule, key, value)
+	}
+
+	return number, nil
+}
+
+func parseNumbers(key, value string, min, max int, allowNegative bool) ([]int, error) {
+	var result []int
+
+	for _, element := range strings.Split(value, ",") {
+		number, err := strconv.Atoi(element)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s value %q", ErrInvalidRule, key, element)
+		}
+
+		magnitude := number
+		if allowNegative && number < 0 {
+			magnitude = -number
+		}
+
+		if magnitude < min || magnitude > max {
+			return nil, fmt.Errorf("%w: %s value %d out of range", ErrInvalidRule, key, number)
+		}
+
+		result = append(result, number)
+	}
+
+	return result, nil
+}
+
+func parseWeekdays(value string) ([]weekdayNumber, error) {
+	var result []weekdayNumber
+
+	for _, element := range strings.Split(value, ",") {
+		element = strings.ToUpper(element)
+		if len(element) < 2 {
+			return nil, fmt.Errorf("%w: invalid BYDAY value %q", ErrInvalidRule, element)
+		}
+
+		weekday, ok := weekdays[element[len(element)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown week day %q", ErrInvalidRule, element)
+		}
+
+		n := 0
+		if ordinal := element[:len(element)-2]; ordinal != "" {
+			var err error
+			if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("%w: invalid BYDAY value %q", ErrInvalidRule, element)
+			}
+		}
+
+		result = append(result, weekdayNumber{weekday: weekday, n: n})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var dtstart = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	until := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		text       string
+		want       *Rule
+		requireErr bool
+	}{
+		{
+			name: "defaults",
+			text: "FREQ=DAILY",
+			want: &Rule{
+				frequency: Daily,
+				interval:  1,
+				weekStart: time.Monday,
+				start:     dtstart,
+			},
+		},
+		{
+			name: "rrule prefix and by parts",
+			text: "RRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=MO,-1FR;BYHOUR=9,17;BYSETPOS=1,-1;WKST=SU",
+			want: &Rule{
+				frequency: Monthly,
+				interval:  2,
+				count:     10,
+				byHour:    []int{9, 17},
+				byDay:     []weekdayNumber{{weekday: time.Monday}, {weekday: time.Friday, n: -1}},
+				bySetPos:  []int{1, -1},
+				weekStart: time.Sunday,
+				start:     dtstart,
+			},
+		},
+		{
+			name: "until",
+			text: "FREQ=WEEKLY;UNTIL=20241231T235959Z",
+			want: &Rule{
+				frequency: Weekly,
+				interval:  1,
+				until:     &until,
+				byDay:     []weekdayNumber{{weekday: time.Monday}},
+				weekStart: time.Monday,
+				start:     dtstart,
+			},
+		},
+		{
+			name: "yearly defaults",
+			text: "FREQ=YEARLY;BYMONTHDAY=-1;BYMONTH=2",
+			want: &Rule{
+				frequency:  Yearly,
+				interval:   1,
+				byMonthDay: []int{-1},
+				byMonth:    []int{2},
+				weekStart:  time.Monday,
+				start:      dtstart,
+			},
+		},
+		{
+			name: "dtstart line",
+			text: "DTSTART;TZID=Europe/Berlin:20240301T080000\nRRULE:FREQ=DAILY;BYMINUTE=0,30",
+			want: &Rule{
+				frequency: Daily,
+				interval:  1,
+				byMinute:  []int{0, 30},
+				weekStart: time.Monday,
+				start:     time.Date(2024, 3, 1, 8, 0, 0, 0, berlin),
+			},
+		},
+		{name: "empty", text: "", requireErr: true},
+		{name: "missing frequency", text: "INTERVAL=2", requireErr: true},
+		{name: "unknown frequency", text: "FREQ=FORTNIGHTLY", requireErr: true},
+		{name: "unsupported part", text: "FREQ=DAILY;BYWEEKNO=1", requireErr: true},
+		{name: "malformed part", text: "FREQ=DAILY;COUNT", requireErr: true},
+		{name: "zero interval", text: "FREQ=DAILY;INTERVAL=0", requireErr: true},
+		{name: "count and until", text: "FREQ=DAILY;COUNT=2;UNTIL=20240301", requireErr: true},
+		{name: "hour out of range", text: "FREQ=DAILY;BYHOUR=24", requireErr: true},
+		{name: "zero month day", text: "FREQ=MONTHLY;BYMONTHDAY=0", requireErr: true},
+		{name: "unknown week day", text: "FREQ=WEEKLY;BYDAY=XY", requireErr: true},
+		{name: "numbered week day in weekly rule", text: "FREQ=WEEKLY;BYDAY=1MO", requireErr: true},
+		{name: "invalid until", text: "FREQ=DAILY;UNTIL=tomorrow", requireErr: true},
+		{name: "unknown time zone", text: "DTSTART;TZID=Nowhere/Else:20240101T000000\nFREQ=DAILY", requireErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.text, dtstart)
+			if tt.requireErr {
+				require.ErrorIs(t, err, ErrInvalidRule)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	t.Parallel()
+
+	require.NotPanics(t, func() { MustParse("FREQ=HOURLY", dtstart) })
+	require.Panics(t, func() { MustParse("FREQ=", dtstart) })
+}
@@ -0,0 +1,472 @@
+package rrule
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// maxSearchYears limits the time span without any occurrence before a
+// Rule is considered to not recur anymore, e.g. for
+// "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30". It is the cycle after which the
+// Gregorian calendar repeats itself.
+const maxSearchYears = 400
+
+const secondsPerDay = 24 * 60 * 60
+
+var _ timestone.Schedule = (*Rule)(nil)
+
+// Frequency is the FREQ part of a Rule.
+type Frequency int
+
+const (
+	Secondly Frequency = iota + 1
+	Minutely
+	Hourly
+	Daily
+	Weekly
+	Monthly
+	Yearly
+)
+
+type weekdayNumber struct {
+	weekday time.Weekday
+	// n is the ordinal of the weekday within the month or year, e.g. 1
+	// for the first or -1 for the last one. It is zero for every weekday.
+	n int
+}
+
+// Rule is a parsed RFC 5545 recurrence rule, anchored at a start time.
+// Use Parse or MustParse to create one. It implements timestone.Schedule.
+type Rule struct {
+	frequency Frequency
+	interval  int
+	count     int
+	until     *time.Time
+
+	bySecond   []int
+	byMinute   []int
+	byHour     []int
+	byDay      []weekdayNumber
+	byMonthDay []int
+	byYearDay  []int
+	byMonth    []int
+	bySetPos   []int
+	weekStart  time.Weekday
+
+	start time.Time
+
+	// The occurrences of a Rule with COUNT are expanded period by period
+	// and cached, since they have to be counted from the start anyway and
+	// are limited by COUNT. Other Rule s jump to the period of the time
+	// they are asked for instead.
+	occurrences []time.Time
+	nextPeriod  int64
+	finished    bool
+	mu          sync.Mutex
+}
+
+// Next returns the first occurrence of the Rule strictly after after.
+// The second return value is false if the Rule doesn't recur anymore.
+func (r *Rule) Next(after time.Time) (time.Time, bool) {
+	if r.count > 0 {
+		return r.nextCounted(after)
+	}
+
+	// Start with the previous period, whose occurrences might be shifted
+	// past after by daylight saving time transitions.
+	period := max(r.periodAt(after)-1, 0)
+
+	since := after
+	if since.Before(r.start) {
+		since = r.start
+	}
+	limit := r.wallClock(since).AddDate(maxSearchYears, 0, 0)
+
+	for ; r.within(period, limit); period = r.following(period) {
+		for _, candidate := range r.expand(period) {
+			if r.until != nil && candidate.After(*r.until) {
+				return time.Time{}, false
+			}
+
+			if candidate.After(after) {
+				return candidate, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (r *Rule) nextCounted(after time.Time) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for {
+		index := sort.Search(len(r.occurrences), func(i int) bool { return r.occurrences[i].After(after) })
+		if index < len(r.occurrences) {
+			return r.occurrences[index], true
+		}
+
+		if r.finished {
+			return time.Time{}, false
+		}
+
+		r.expandNextPeriod()
+	}
+}
+
+// applyDefaults derives the day of the occurrences from the start time,
+// if the rule doesn't specify it otherwise.
+func (r *Rule) applyDefaults() {
+	noDayRules := len(r.byYearDay) == 0 && len(r.byMonthDay) == 0 && len(r.byDay) == 0
+
+	switch r.frequency {
+	case Yearly:
+		if noDayRules {
+			if len(r.byMonth) == 0 {
+				r.byMonth = []int{int(r.start.Month())}
+			}
+			r.byMonthDay = []int{r.start.Day()}
+		}
+	case Monthly:
+		if noDayRules {
+			r.byMonthDay = []int{r.start.Day()}
+		}
+	case Weekly:
+		if noDayRules {
+			r.byDay = []weekdayNumber{{weekday: r.start.Weekday()}}
+		}
+	default:
+	}
+}
+
+// expandNextPeriod caches the occurrences of the next period that has
+// any, counting them towards COUNT.
+func (r *Rule) expandNextPeriod() {
+	since := r.start
+	if len(r.occurrences) > 0 {
+		since = r.occurrences[len(r.occurrences)-1]
+	}
+	limit := r.wallClock(since).AddDate(maxSearchYears, 0, 0)
+
+	for period := r.nextPeriod; r.within(period, limit); period = r.following(period) {
+		candidates := r.expand(period)
+		if len(candidates) == 0 {
+			continue
+		}
+		r.nextPeriod = period + 1
+
+		for _, candidate := range candidates {
+			if r.until != nil && candidate.After(*r.until) {
+				r.finished = true
+				return
+			}
+
+			r.occurrences = append(r.occurrences, candidate)
+
+			if len(r.occurrences) >= r.count {
+				r.finished = true
+				return
+			}
+		}
+
+		return
+	}
+
+	r.finished = true
+}
+
+// expand returns the occurrences of period in order.
+func (r *Rule) expand(period int64) []time.Time {
+	candidates := r.periodCandidates(period)
+	slices.SortFunc(candidates, time.Time.Compare)
+	candidates = slices.CompactFunc(candidates, time.Time.Equal)
+	candidates = r.applySetPos(candidates)
+
+	return slices.DeleteFunc(candidates, func(candidate time.Time) bool { return candidate.Before(r.start) })
+}
+
+// wallClock returns the wall clock time of t in the location of the
+// start as UTC, in which the periods are computed.
+func (r *Rule) wallClock(t time.Time) time.Time {
+	t = t.In(r.start.Location())
+
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// periodStart returns the wall clock time period starts at.
+func (r *Rule) periodStart(period int64) time.Time {
+	step := period * int64(r.interval)
+	start := r.wallClock(r.start)
+	startDate := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch r.frequency {
+	case Yearly:
+		return time.Date(start.Year()+int(step), time.January, 1, 0, 0, 0, 0, time.UTC)
+	case Monthly:
+		return time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.weekStart) + 7) % 7
+		return startDate.AddDate(0, 0, int(step)*7-offset)
+	case Daily:
+		return startDate.AddDate(0, 0, int(step))
+	default:
+		unit := r.unit()
+		return time.Unix(start.Truncate(unit).Unix()+step*int64(unit/time.Second), 0).UTC()
+	}
+}
+
+// periodAt returns the period containing the wall clock time of t, which
+// is negative before the start.
+func (r *Rule) periodAt(t time.Time) int64 {
+	wall, start := r.wallClock(t), r.wallClock(r.start)
+
+	switch r.frequency {
+	case Yearly:
+		return int64(wall.Year()-start.Year()) / int64(r.interval)
+	case Monthly:
+		months := int64(wall.Year()-start.Year())*12 + int64(wall.Month()-start.Month())
+		return months / int64(r.interval)
+	default:
+		return (wall.Unix() - r.periodStart(0).Unix()) / r.periodSeconds()
+	}
+}
+
+// following returns the next period after period that might have any
+// occurrences, skipping periods shorter than a day whose day, hour or
+// minute doesn't match the Rule.
+func (r *Rule) following(period int64) int64 {
+	if r.frequency >= Daily {
+		return period + 1
+	}
+
+	base := r.periodStart(period)
+
+	var next time.Time
+	switch {
+	case !r.dayMatches(base):
+		next = time.Date(base.Year(), base.Month(), base.Day()+1, 0, 0, 0, 0, time.UTC)
+	case !matches(r.byHour, base.Hour()):
+		next = base.Truncate(time.Hour).Add(time.Hour)
+	case r.frequency == Secondly && !matches(r.byMinute, base.Minute()):
+		next = base.Truncate(time.Minute).Add(time.Minute)
+	default:
+		return period + 1
+	}
+
+	length := r.periodSeconds()
+	elapsed := next.Unix() - r.periodStart(0).Unix()
+
+	return max((elapsed+length-1)/length, period+1)
+}
+
+// within reports whether period starts before limit and the year 10000.
+func (r *Rule) within(period int64, limit time.Time) bool {
+	start := r.periodStart(period)
+
+	return !start.After(limit) && start.Year() <= 9999
+}
+
+// unit returns the duration of a period without INTERVAL for
+// frequencies shorter than a day.
+func (r *Rule) unit() time.Duration {
+	switch r.frequency {
+	case Hourly:
+		return time.Hour
+	case Minutely:
+		return time.Minute
+	default:
+		return time.Second
+	}
+}
+
+// periodSeconds returns the length of a period in seconds for weekly and
+// shorter frequencies.
+func (r *Rule) periodSeconds() int64 {
+	switch r.frequency {
+	case Weekly:
+		return int64(r.interval) * 7 * secondsPerDay
+	case Daily:
+		return int64(r.interval) * secondsPerDay
+	default:
+		return int64(r.interval) * int64(r.unit()/time.Second)
+	}
+}
+
+func (r *Rule) periodCandidates(period int64) []time.Time {
+	first := r.periodStart(period)
+
+	var days []time.Time
+	switch r.frequency {
+	case Yearly:
+		for day := first; day.Year() == first.Year(); day = day.AddDate(0, 0, 1) {
+			days = append(days, day)
+		}
+	case Monthly:
+		for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+			days = append(days, day)
+		}
+	case Weekly:
+		for i := range 7 {
+			days = append(days, first.AddDate(0, 0, i))
+		}
+	case Daily:
+		days = append(days, first)
+	default:
+		return r.subDailyCandidates(first)
+	}
+
+	var result []time.Time
+	for _, day := range days {
+		if !r.dayMatches(day) {
+			continue
+		}
+
+		for _, hour := range orDefault(r.byHour, r.start.Hour()) {
+			for _, minute := range orDefault(r.byMinute, r.start.Minute()) {
+				for _, second := range orDefault(r.bySecond, r.start.Second()) {
+					result = append(result, r.instant(day, hour, minute, second))
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// subDailyCandidates expands the occurrences of a period shorter than a
+// day, starting at the wall clock time base.
+func (r *Rule) subDailyCandidates(base time.Time) []time.Time {
+	if !r.dayMatches(base) || !matches(r.byHour, base.Hour()) {
+		return nil
+	}
+
+	minutes := orDefault(r.byMinute, r.start.Minute())
+	if r.frequency <= Minutely {
+		if !matches(r.byMinute, base.Minute()) {
+			return nil
+		}
+		minutes = []int{base.Minute()}
+	}
+
+	seconds := orDefault(r.bySecond, r.start.Second())
+	if r.frequency == Secondly {
+		if !matches(r.bySecond, base.Second()) {
+			return nil
+		}
+		seconds = []int{base.Second()}
+	}
+
+	var result []time.Time
+	for _, minute := range minutes {
+		for _, second := range seconds {
+			result = append(result, r.instant(base, base.Hour(), minute, second))
+		}
+	}
+
+	return result
+}
+
+func (r *Rule) instant(day time.Time, hour, minute, second int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, r.start.Location())
+}
+
+func (r *Rule) dayMatches(day time.Time) bool {
+	if !matches(r.byMonth, int(day.Month())) {
+		return false
+	}
+
+	daysInYear := time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	if !matchesSigned(r.byYearDay, day.YearDay(), daysInYear) {
+		return false
+	}
+
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if !matchesSigned(r.byMonthDay, day.Day(), daysInMonth) {
+		return false
+	}
+
+	if len(r.byDay) == 0 {
+		return true
+	}
+
+	for _, weekday := range r.byDay {
+		if weekday.weekday != day.Weekday() {
+			continue
+		}
+
+		if weekday.n == 0 {
+			return true
+		}
+
+		// Numbered weekdays are counted within the month, unless the
+		// rule is yearly without being restricted to months.
+		position, length := day.Day(), daysInMonth
+		if r.frequency == Yearly && len(r.byMonth) == 0 {
+			position, length = day.YearDay(), daysInYear
+		}
+
+		if weekday.n > 0 && (position-1)/7+1 == weekday.n {
+			return true
+		}
+		if weekday.n < 0 && (length-position)/7+1 == -weekday.n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Rule) applySetPos(candidates []time.Time) []time.Time {
+	if len(r.bySetPos) == 0 {
+		return candidates
+	}
+
+	var result []time.Time
+	for _, position := range r.bySetPos {
+		index := position - 1
+		if position < 0 {
+			index = len(candidates) + position
+		}
+
+		if index >= 0 && index < len(candidates) {
+			result = append(result, candidates[index])
+		}
+	}
+
+	slices.SortFunc(result, time.Time.Compare)
+
+	return slices.CompactFunc(result, time.Time.Equal)
+}
+
+func orDefault(values []int, fallback int) []int {
+	if len(values) == 0 {
+		return []int{fallback}
+	}
+
+	return values
+}
+
+func matches(values []int, value int) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
+
+// matchesSigned is like matches, but also considers negative values
+// which count backwards from length.
+func matchesSigned(values []int, value, length int) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, candidate := range values {
+		if candidate == value || (candidate < 0 && length+1+candidate == value) {
+			return true
+		}
+	}
+
+	return false
+}
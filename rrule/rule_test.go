@@ -0,0 +1,237 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestRule_Next(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		text    string
+		dtstart time.Time
+		after   time.Time
+		want    []time.Time
+	}{
+		{
+			name: "first monday of the month",
+			text: "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1",
+			want: []time.Time{
+				date(2024, 1, 1, 9, 0),
+				date(2024, 2, 5, 9, 0),
+				date(2024, 3, 4, 9, 0),
+				date(2024, 4, 1, 9, 0),
+			},
+		},
+		{
+			name: "last friday of the month",
+			text: "FREQ=MONTHLY;BYDAY=-1FR",
+			want: []time.Time{
+				date(2024, 1, 26, 9, 0),
+				date(2024, 2, 23, 9, 0),
+				date(2024, 3, 29, 9, 0),
+			},
+		},
+		{
+			name: "last day of the month",
+			text: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			want: []time.Time{
+				date(2024, 1, 31, 9, 0),
+				date(2024, 2, 29, 9, 0),
+				date(2024, 3, 31, 9, 0),
+			},
+		},
+		{
+			name: "count",
+			text: "FREQ=DAILY;COUNT=3",
+			want: []time.Time{
+				date(2024, 1, 1, 9, 0),
+				date(2024, 1, 2, 9, 0),
+				date(2024, 1, 3, 9, 0),
+			},
+		},
+		{
+			name:  "count is counted from dtstart",
+			text:  "FREQ=DAILY;COUNT=3",
+			after: date(2024, 1, 2, 0, 0),
+			want: []time.Time{
+				date(2024, 1, 2, 9, 0),
+				date(2024, 1, 3, 9, 0),
+			},
+		},
+		{
+			name: "until is inclusive",
+			text: "FREQ=WEEKLY;BYDAY=MO,WE,FR;UNTIL=20240110T090000Z",
+			want: []time.Time{
+				date(2024, 1, 1, 9, 0),
+				date(2024, 1, 3, 9, 0),
+				date(2024, 1, 5, 9, 0),
+				date(2024, 1, 8, 9, 0),
+				date(2024, 1, 10, 9, 0),
+			},
+		},
+		{
+			name:    "week start sunday",
+			text:    "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			dtstart: date(1997, 8, 5, 9, 0),
+			want: []time.Time{
+				date(1997, 8, 5, 9, 0),
+				date(1997, 8, 17, 9, 0),
+				date(1997, 8, 19, 9, 0),
+				date(1997, 8, 31, 9, 0),
+			},
+		},
+		{
+			name:    "week start monday",
+			text:    "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			dtstart: date(1997, 8, 5, 9, 0),
+			want: []time.Time{
+				date(1997, 8, 5, 9, 0),
+				date(1997, 8, 10, 9, 0),
+				date(1997, 8, 19, 9, 0),
+				date(1997, 8, 24, 9, 0),
+			},
+		},
+		{
+			name:    "leap day",
+			text:    "FREQ=YEARLY",
+			dtstart: date(2024, 2, 29, 0, 0),
+			want: []time.Time{
+				date(2024, 2, 29, 0, 0),
+				date(2028, 2, 29, 0, 0),
+			},
+		},
+		{
+			name: "numbered week day of the year",
+			text: "FREQ=YEARLY;BYDAY=20MO",
+			want: []time.Time{
+				date(2024, 5, 13, 9, 0),
+				date(2025, 5, 19, 9, 0),
+			},
+		},
+		{
+			name: "times of day",
+			text: "FREQ=DAILY;BYHOUR=9,17;BYMINUTE=0,30",
+			want: []time.Time{
+				date(2024, 1, 1, 9, 0),
+				date(2024, 1, 1, 9, 30),
+				date(2024, 1, 1, 17, 0),
+				date(2024, 1, 1, 17, 30),
+				date(2024, 1, 2, 9, 0),
+			},
+		},
+		{
+			name: "hourly",
+			text: "FREQ=HOURLY;INTERVAL=6;BYHOUR=9,15,21",
+			want: []time.Time{
+				date(2024, 1, 1, 9, 0),
+				date(2024, 1, 1, 15, 0),
+				date(2024, 1, 1, 21, 0),
+				date(2024, 1, 2, 9, 0),
+			},
+		},
+		{
+			name: "minutely",
+			text: "FREQ=MINUTELY;INTERVAL=90",
+			want: []time.Time{
+				date(2024, 1, 1, 9, 0),
+				date(2024, 1, 1, 10, 30),
+				date(2024, 1, 1, 12, 0),
+			},
+		},
+		{
+			name: "minutely within an hour of every day",
+			text: "FREQ=MINUTELY;INTERVAL=20;BYHOUR=9",
+			want: []time.Time{
+				date(2024, 1, 1, 9, 0),
+				date(2024, 1, 1, 9, 20),
+				date(2024, 1, 1, 9, 40),
+				date(2024, 1, 2, 9, 0),
+				date(2024, 1, 2, 9, 20),
+			},
+		},
+		{
+			name: "secondly on a single day of the year",
+			text: "FREQ=SECONDLY;BYMONTH=6;BYMONTHDAY=1;BYHOUR=12;BYMINUTE=0;BYSECOND=0,30",
+			want: []time.Time{
+				date(2024, 6, 1, 12, 0),
+				date(2024, 6, 1, 12, 0).Add(30 * time.Second),
+				date(2025, 6, 1, 12, 0),
+			},
+		},
+		{
+			name:  "far future",
+			text:  "FREQ=HOURLY;INTERVAL=5",
+			after: date(2999, 12, 31, 23, 0),
+			want: []time.Time{
+				date(3000, 1, 1, 1, 0),
+				date(3000, 1, 1, 6, 0),
+			},
+		},
+		{
+			name:  "long before the start",
+			text:  "FREQ=DAILY",
+			after: time.Time{}.Add(time.Hour),
+			want: []time.Time{
+				date(2024, 1, 1, 9, 0),
+				date(2024, 1, 2, 9, 0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			start := tt.dtstart
+			if start.IsZero() {
+				start = dtstart
+			}
+
+			after := tt.after
+			if after.IsZero() {
+				after = start.Add(-time.Second)
+			}
+
+			rule := MustParse(tt.text, start)
+
+			got := make([]time.Time, 0, len(tt.want))
+			for range len(tt.want) {
+				next, ok := rule.Next(after)
+				if !ok {
+					break
+				}
+
+				got = append(got, next)
+				after = next
+			}
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRule_Next_finite(t *testing.T) {
+	t.Parallel()
+
+	rule := MustParse("FREQ=DAILY;COUNT=2", dtstart)
+
+	_, ok := rule.Next(dtstart.Add(24 * time.Hour))
+	require.False(t, ok)
+
+	next, ok := rule.Next(dtstart)
+	require.True(t, ok)
+	require.Equal(t, dtstart.Add(24*time.Hour), next)
+
+	never := MustParse("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", dtstart)
+	_, ok = never.Next(dtstart)
+	require.False(t, ok)
+}
@@ -0,0 +1,56 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// Filter returns a Schedule with all points in time of schedule for
+// which keep returns true.
+func Filter(schedule timestone.Schedule, keep func(time.Time) bool) timestone.Schedule {
+	return Func(func(after time.Time) (time.Time, bool) {
+		for range maxSearchSteps {
+			next, ok := schedule.Next(after)
+			if !ok {
+				return time.Time{}, false
+			}
+
+			if keep(next) {
+				return next, true
+			}
+
+			after = next
+		}
+
+		return time.Time{}, false
+	})
+}
+
+// BusinessDays returns a Schedule with all points in time of schedule
+// that are neither on a weekend nor on the same date as any of holidays.
+// Dates are compared in the location of the respective point in time of
+// schedule.
+func BusinessDays(schedule timestone.Schedule, holidays ...time.Time) timestone.Schedule {
+	type date struct {
+		year  int
+		month time.Month
+		day   int
+	}
+
+	holidayDates := make(map[date]bool, len(holidays))
+	for _, holiday := range holidays {
+		year, month, day := holiday.Date()
+		holidayDates[date{year, month, day}] = true
+	}
+
+	return Filter(schedule, func(t time.Time) bool {
+		if weekday := t.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			return false
+		}
+
+		year, month, day := t.Date()
+
+		return !holidayDates[date{year, month, day}]
+	})
+}
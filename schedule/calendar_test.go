@@ -0,0 +1,45 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	schedule := Filter(Every(now, time.Hour), func(t time.Time) bool { return t.Hour()%6 == 0 })
+
+	require.Equal(t,
+		[]time.Time{now.Add(6 * time.Hour), now.Add(12 * time.Hour)},
+		collect(schedule, now, 2),
+	)
+
+	never := Filter(Every(now, time.Hour), func(time.Time) bool { return false })
+	_, ok := never.Next(now)
+	require.False(t, ok)
+}
+
+func TestBusinessDays(t *testing.T) {
+	t.Parallel()
+
+	// Friday, 2024-12-20
+	start := time.Date(2024, 12, 20, 9, 0, 0, 0, time.UTC)
+	christmas := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+	boxingDay := time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)
+
+	schedule := BusinessDays(Every(start, 24*time.Hour), christmas, boxingDay)
+
+	require.Equal(t,
+		[]time.Time{
+			time.Date(2024, 12, 20, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 23, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 24, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 27, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 30, 9, 0, 0, 0, time.UTC),
+		},
+		collect(schedule, start.Add(-time.Second), 5),
+	)
+}
@@ -0,0 +1,159 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// Union returns a Schedule with all points in time of any of schedules.
+func Union(schedules ...timestone.Schedule) timestone.Schedule {
+	return Func(func(after time.Time) (time.Time, bool) {
+		var earliest time.Time
+		found := false
+
+		for _, schedule := range schedules {
+			next, ok := schedule.Next(after)
+			if !ok {
+				continue
+			}
+
+			if !found || next.Before(earliest) {
+				earliest = next
+				found = true
+			}
+		}
+
+		return earliest, found
+	})
+}
+
+// Intersection returns a Schedule with the points in time that are part
+// of all schedules.
+func Intersection(schedules ...timestone.Schedule) timestone.Schedule {
+	if len(schedules) == 0 {
+		panic("at least one schedule is required")
+	}
+
+	return Func(func(after time.Time) (time.Time, bool) {
+		for range maxSearchSteps {
+			candidate, ok := schedules[0].Next(after)
+			if !ok {
+				return time.Time{}, false
+			}
+
+			latest := candidate
+			for _, schedule := range schedules[1:] {
+				next, ok := schedule.Next(candidate.Add(-time.Nanosecond))
+				if !ok {
+					return time.Time{}, false
+				}
+
+				if next.After(latest) {
+					latest = next
+				}
+			}
+
+			if latest.Equal(candidate) {
+				return candidate, true
+			}
+
+			after = latest.Add(-time.Nanosecond)
+		}
+
+		return time.Time{}, false
+	})
+}
+
+// Except returns a Schedule with the points in time of schedule that are
+// not part of excluded.
+func Except(schedule, excluded timestone.Schedule) timestone.Schedule {
+	return Filter(schedule, func(t time.Time) bool {
+		return !contains(excluded, t)
+	})
+}
+
+// From returns a Schedule with the points in time of schedule at or
+// after start.
+func From(schedule timestone.Schedule, start time.Time) timestone.Schedule {
+	return Func(func(after time.Time) (time.Time, bool) {
+		if after.Before(start) {
+			after = start.Add(-time.Nanosecond)
+		}
+
+		return schedule.Next(after)
+	})
+}
+
+// Until returns a Schedule with the points in time of schedule at or
+// before end.
+func Until(schedule timestone.Schedule, end time.Time) timestone.Schedule {
+	return Func(func(after time.Time) (time.Time, bool) {
+		next, ok := schedule.Next(after)
+		if !ok || next.After(end) {
+			return time.Time{}, false
+		}
+
+		return next, true
+	})
+}
+
+// Limit returns a Schedule with the first n points in time of schedule
+// after start. As they are counted from start rather than from the first
+// call to Next, the Schedule returns the same points in time no matter
+// when it is started to be used, or how often.
+func Limit(schedule timestone.Schedule, start time.Time, n int) timestone.Schedule {
+	if n < 0 {
+		panic("n must not be negative")
+	}
+
+	return &limit{
+		schedule: schedule,
+		start:    start,
+		n:        n,
+	}
+}
+
+type limit struct {
+	schedule timestone.Schedule
+	start    time.Time
+	n        int
+
+	// times caches the points in time found so far, which only depend on
+	// start.
+	times    []time.Time
+	finished bool
+	mu       sync.Mutex
+}
+
+func (l *limit) Next(after time.Time) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, t := range l.times {
+		if t.After(after) {
+			return t, true
+		}
+	}
+
+	for !l.finished && len(l.times) < l.n {
+		previous := l.start
+		if len(l.times) > 0 {
+			previous = l.times[len(l.times)-1]
+		}
+
+		next, ok := l.schedule.Next(previous)
+		if !ok {
+			l.finished = true
+			break
+		}
+
+		l.times = append(l.times, next)
+		if next.After(after) {
+			return next, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,115 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2/cron"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnion(t *testing.T) {
+	t.Parallel()
+
+	schedule := Union(Every(now, 2*time.Hour), Every(now, 3*time.Hour), At())
+
+	require.Equal(t,
+		[]time.Time{
+			now.Add(2 * time.Hour),
+			now.Add(3 * time.Hour),
+			now.Add(4 * time.Hour),
+			now.Add(6 * time.Hour),
+			now.Add(8 * time.Hour),
+		},
+		collect(schedule, now, 5),
+	)
+
+	_, ok := Union().Next(now)
+	require.False(t, ok)
+}
+
+func TestIntersection(t *testing.T) {
+	t.Parallel()
+
+	schedule := Intersection(Every(now, 2*time.Hour), Every(now, 3*time.Hour))
+
+	require.Equal(t,
+		[]time.Time{now.Add(6 * time.Hour), now.Add(12 * time.Hour)},
+		collect(schedule, now, 2),
+	)
+
+	disjoint := Intersection(Every(now, 2*time.Hour), Every(now.Add(time.Hour), 2*time.Hour))
+	_, ok := disjoint.Next(now)
+	require.False(t, ok)
+
+	finite := Intersection(Every(now, time.Hour), At(now.Add(time.Hour)))
+	require.Equal(t, []time.Time{now.Add(time.Hour)}, collect(finite, now, 5))
+
+	require.Panics(t, func() { Intersection() })
+}
+
+func TestExcept(t *testing.T) {
+	t.Parallel()
+
+	schedule := Except(Every(now, time.Hour), Every(now, 2*time.Hour))
+
+	require.Equal(t,
+		[]time.Time{now.Add(time.Hour), now.Add(3 * time.Hour), now.Add(5 * time.Hour)},
+		collect(schedule, now, 3),
+	)
+}
+
+func TestFrom(t *testing.T) {
+	t.Parallel()
+
+	schedule := From(Every(now, time.Hour), now.Add(3*time.Hour))
+
+	require.Equal(t,
+		[]time.Time{now.Add(3 * time.Hour), now.Add(4 * time.Hour)},
+		collect(schedule, now, 2),
+	)
+	require.Equal(t,
+		[]time.Time{now.Add(5 * time.Hour)},
+		collect(schedule, now.Add(4*time.Hour), 1),
+	)
+}
+
+func TestUntil(t *testing.T) {
+	t.Parallel()
+
+	schedule := Until(Every(now, time.Hour), now.Add(3*time.Hour))
+
+	require.Equal(t,
+		[]time.Time{now.Add(time.Hour), now.Add(2 * time.Hour), now.Add(3 * time.Hour)},
+		collect(schedule, now, 5),
+	)
+}
+
+func TestLimit(t *testing.T) {
+	t.Parallel()
+
+	schedule := Limit(cron.MustParse("@hourly"), now.Add(-time.Second), 3)
+
+	require.Equal(t,
+		[]time.Time{now, now.Add(time.Hour), now.Add(2 * time.Hour)},
+		collect(schedule, now.Add(-time.Second), 5),
+	)
+	require.Equal(t,
+		[]time.Time{now.Add(2 * time.Hour)},
+		collect(schedule, now.Add(time.Hour), 5),
+	)
+
+	// The points in time are counted from start, no matter which time
+	// Next is called with first.
+	late := Limit(cron.MustParse("@hourly"), now.Add(-time.Second), 3)
+	require.Equal(t, []time.Time{now.Add(2 * time.Hour)}, collect(late, now.Add(time.Hour), 5))
+	require.Equal(t,
+		[]time.Time{now, now.Add(time.Hour), now.Add(2 * time.Hour)},
+		collect(late, now.Add(-time.Hour), 5),
+	)
+
+	finite := Limit(At(now.Add(time.Hour)), now, 3)
+	require.Equal(t, []time.Time{now.Add(time.Hour)}, collect(finite, now, 5))
+
+	require.Panics(t, func() { Limit(At(), now, -1) })
+}
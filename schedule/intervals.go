@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// Exponential returns a Schedule that starts at start, with the interval
+// to the following point in time being initial and growing by factor
+// after every point in time. If max is greater than zero, the interval
+// won't grow beyond max.
+func Exponential(start time.Time, initial time.Duration, factor float64, max time.Duration) timestone.Schedule {
+	if initial <= 0 {
+		panic("initial interval must be greater than zero")
+	}
+
+	if factor < 1 {
+		panic("factor must not be smaller than one")
+	}
+
+	return Func(func(after time.Time) (time.Time, bool) {
+		next := start
+		interval := float64(initial)
+
+		for !next.After(after) {
+			// Skip ahead once the interval doesn't grow anymore
+			if max > 0 && interval >= float64(max) {
+				elapsedIntervals := after.Sub(next)/max + 1
+				return next.Add(elapsedIntervals * max), true
+			}
+			if factor == 1 {
+				elapsedIntervals := after.Sub(next)/initial + 1
+				return next.Add(elapsedIntervals * initial), true
+			}
+
+			if interval > math.MaxInt64 {
+				return time.Time{}, false
+			}
+
+			next = next.Add(time.Duration(interval))
+			interval *= factor
+		}
+
+		return next, true
+	})
+}
+
+// Random returns a Schedule that starts at start, with the intervals
+// between its points in time being uniformly distributed between min,
+// inclusive, and max, exclusive, like a config.Uniform duration of the
+// simulation. If max equals min, all intervals are min. The intervals are drawn from a pseudo random number generator
+// initialized with seed, so that the same seed always yields the same
+// Schedule.
+func Random(start time.Time, min, max time.Duration, seed uint64) timestone.Schedule {
+	if min <= 0 {
+		panic("min must be greater than zero")
+	}
+
+	if max < min {
+		panic("max must not be smaller than min")
+	}
+
+	return &random{
+		random: rand.New(rand.NewPCG(seed, seed)),
+		min:    min,
+		max:    max,
+		times:  []time.Time{start},
+	}
+}
+
+type random struct {
+	random   *rand.Rand
+	min, max time.Duration
+
+	times []time.Time
+	mu    sync.Mutex
+}
+
+func (r *random) Next(after time.Time) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for !r.times[len(r.times)-1].After(after) {
+		interval := r.min
+		if r.max > r.min {
+			interval += time.Duration(r.random.Int64N(int64(r.max - r.min)))
+		}
+		r.times = append(r.times, r.times[len(r.times)-1].Add(interval))
+	}
+
+	index := sort.Search(len(r.times), func(i int) bool { return r.times[i].After(after) })
+
+	return r.times[index], true
+}
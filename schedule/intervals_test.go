@@ -0,0 +1,142 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExponential(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		initial time.Duration
+		factor  float64
+		max     time.Duration
+		after   time.Time
+		want    []time.Time
+	}{
+		{
+			name:    "uncapped",
+			initial: time.Second,
+			factor:  2,
+			after:   now,
+			want: []time.Time{
+				now.Add(1 * time.Second),
+				now.Add(3 * time.Second),
+				now.Add(7 * time.Second),
+				now.Add(15 * time.Second),
+			},
+		},
+		{
+			name:    "capped",
+			initial: time.Second,
+			factor:  2,
+			max:     4 * time.Second,
+			after:   now.Add(5 * time.Second),
+			want: []time.Time{
+				now.Add(7 * time.Second),
+				now.Add(11 * time.Second),
+				now.Add(15 * time.Second),
+			},
+		},
+		{
+			name:    "capped far after start",
+			initial: time.Second,
+			factor:  2,
+			max:     4 * time.Second,
+			after:   now.Add(time.Hour),
+			want: []time.Time{
+				now.Add(time.Hour + 3*time.Second),
+				now.Add(time.Hour + 7*time.Second),
+			},
+		},
+		{
+			name:    "constant",
+			initial: time.Minute,
+			factor:  1,
+			after:   now.Add(24 * time.Hour),
+			want: []time.Time{
+				now.Add(24*time.Hour + time.Minute),
+				now.Add(24*time.Hour + 2*time.Minute),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule := Exponential(now, tt.initial, tt.factor, tt.max)
+			require.Equal(t, tt.want, collect(schedule, tt.after, len(tt.want)))
+		})
+	}
+}
+
+func TestExponential_overflow(t *testing.T) {
+	t.Parallel()
+
+	schedule := Exponential(now, time.Hour, 1000, 0)
+
+	require.Len(t, collect(schedule, now, 100), 3)
+}
+
+func TestExponential_invalid(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() { Exponential(now, 0, 2, 0) })
+	require.Panics(t, func() { Exponential(now, time.Second, 0.5, 0) })
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	first := collect(Random(now, time.Minute, time.Hour, 42), now, 50)
+	second := collect(Random(now, time.Minute, time.Hour, 42), now, 50)
+	other := collect(Random(now, time.Minute, time.Hour, 7), now, 50)
+
+	require.Equal(t, first, second)
+	require.NotEqual(t, first, other)
+
+	previous := now
+	for _, next := range first {
+		interval := next.Sub(previous)
+		require.GreaterOrEqual(t, interval, time.Minute)
+		require.Less(t, interval, time.Hour)
+
+		previous = next
+	}
+}
+
+func TestRandom_lookBack(t *testing.T) {
+	t.Parallel()
+
+	schedule := Random(now, time.Minute, time.Hour, 42)
+	times := collect(schedule, now, 10)
+
+	next, ok := schedule.Next(now)
+	require.True(t, ok)
+	require.Equal(t, times[0], next)
+
+	next, ok = schedule.Next(now.Add(-time.Second))
+	require.True(t, ok)
+	require.Equal(t, now, next)
+}
+
+func TestRandom_fixed(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		[]time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)},
+		collect(Random(now, time.Minute, time.Minute, 42), now, 2),
+	)
+}
+
+func TestRandom_invalid(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() { Random(now, 0, time.Hour, 1) })
+	require.Panics(t, func() { Random(now, time.Hour, time.Minute, 1) })
+}
@@ -0,0 +1,70 @@
+// Package schedule provides implementations of timestone.Schedule and
+// combinators to compose them.
+//
+// A timestone.Schedule is treated as a set of points in time, so that
+// schedules can be united, intersected and subtracted. Schedules with
+// a beginning, like Every or Exponential, are anchored to an explicit
+// start time rather than to the time they are being used at, e.g.
+//
+//	schedule.Every(scheduler.Now(), time.Hour)
+package schedule
+
+import (
+	"slices"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+// maxSearchSteps limits the number of candidates that are examined by
+// filtering schedules before giving up.
+const maxSearchSteps = 100_000
+
+// Func provides an implementation for timestone.Schedule by a function.
+type Func func(after time.Time) (time.Time, bool)
+
+// Next implements timestone.Schedule and calls the func aliased by Func.
+func (f Func) Next(after time.Time) (time.Time, bool) {
+	return f(after)
+}
+
+// At returns a Schedule consisting of times.
+func At(times ...time.Time) timestone.Schedule {
+	sorted := slices.Clone(times)
+	slices.SortFunc(sorted, time.Time.Compare)
+
+	return Func(func(after time.Time) (time.Time, bool) {
+		for _, t := range sorted {
+			if t.After(after) {
+				return t, true
+			}
+		}
+
+		return time.Time{}, false
+	})
+}
+
+// Every returns a Schedule that starts at start and repeats every
+// interval.
+func Every(start time.Time, interval time.Duration) timestone.Schedule {
+	if interval <= 0 {
+		panic("interval must be greater than zero")
+	}
+
+	return Func(func(after time.Time) (time.Time, bool) {
+		if after.Before(start) {
+			return start, true
+		}
+
+		elapsedIntervals := after.Sub(start)/interval + 1
+
+		return start.Add(elapsedIntervals * interval), true
+	})
+}
+
+// contains reports whether t is part of schedule.
+func contains(schedule timestone.Schedule, t time.Time) bool {
+	next, ok := schedule.Next(t.Add(-time.Nanosecond))
+
+	return ok && next.Equal(t)
+}
@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// collect returns up to limit points in time of schedule after after.
+func collect(schedule timestone.Schedule, after time.Time, limit int) []time.Time {
+	result := make([]time.Time, 0, limit)
+
+	for range limit {
+		next, ok := schedule.Next(after)
+		if !ok {
+			break
+		}
+
+		result = append(result, next)
+		after = next
+	}
+
+	return result
+}
+
+func TestFunc_Next(t *testing.T) {
+	t.Parallel()
+
+	f := Func(func(after time.Time) (time.Time, bool) { return after.Add(time.Second), true })
+
+	next, ok := f.Next(now)
+	require.True(t, ok)
+	require.Equal(t, now.Add(time.Second), next)
+}
+
+func TestAt(t *testing.T) {
+	t.Parallel()
+
+	schedule := At(now.Add(2*time.Hour), now, now.Add(time.Hour))
+
+	require.Equal(t,
+		[]time.Time{now, now.Add(time.Hour), now.Add(2 * time.Hour)},
+		collect(schedule, now.Add(-time.Second), 5),
+	)
+	require.Equal(t,
+		[]time.Time{now.Add(time.Hour), now.Add(2 * time.Hour)},
+		collect(schedule, now, 5),
+	)
+}
+
+func TestEvery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "before start",
+			after: now.Add(-time.Hour),
+			want:  []time.Time{now, now.Add(time.Minute)},
+		},
+		{
+			name:  "at start",
+			after: now,
+			want:  []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)},
+		},
+		{
+			name:  "between points in time",
+			after: now.Add(90 * time.Second),
+			want:  []time.Time{now.Add(2 * time.Minute), now.Add(3 * time.Minute)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, collect(Every(now, time.Minute), tt.after, 2))
+		})
+	}
+
+	require.Panics(t, func() { Every(now, 0) })
+}
+
+func Test_contains(t *testing.T) {
+	t.Parallel()
+
+	schedule := Every(now, time.Minute)
+
+	require.True(t, contains(schedule, now))
+	require.True(t, contains(schedule, now.Add(time.Hour)))
+	require.False(t, contains(schedule, now.Add(time.Second)))
+}
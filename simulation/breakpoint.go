@@ -0,0 +1,111 @@
+package simulation
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+	"github.com/metamogul/timestone/v2/simulation/internal/selector"
+)
+
+// Breakpoint selects the Event s at which the run loop of a Scheduler
+// pauses to call a hook registered with Scheduler.Break. Event s
+// resuming parked actions are never selected.
+type Breakpoint struct {
+	// Selector selects the Event s by their tags. An empty Selector
+	// selects all Event s.
+	Selector config.Selector
+	// Time is optional. If set, only Event s at Time are selected.
+	Time time.Time
+	// After makes the run loop call the hook once the action of the Event
+	// has returned or been parked, instead of before it starts.
+	After bool
+}
+
+// breakpoint is a Breakpoint registered with its hook.
+type breakpoint struct {
+	Breakpoint
+	expression selector.Expression
+	hook       func(event EventInfo)
+}
+
+// breakpoints holds the breakpoint s registered with a Scheduler.
+type breakpoints struct {
+	breakpoints []*breakpoint
+	mu          sync.Mutex
+}
+
+// Break registers hook to be called by the run loop whenever it reaches
+// an Event selected by breakpoint, and returns a function to remove it
+// again. The hook is called on the goroutine calling Forward, or any of
+// the other methods running the loop, which pauses until the hook
+// returns. This hands control back to the test to inspect or mutate the
+// state of the application at that point, as long as the hook doesn't
+// forward the Scheduler itself. A Breakpoint selecting all Event s
+// serves as a hook called at every step of the run loop.
+//
+// For a Breakpoint with After set, the run loop waits for the action of
+// the Event like RunUntilIdle does, so that the Event s following it are
+// no longer executed concurrently with it.
+//
+// Break returns an error, without registering hook, if the Selector of
+// breakpoint can't be parsed.
+func (s *Scheduler) Break(breakpoint Breakpoint, hook func(event EventInfo)) (clear func(), err error) {
+	return s.breakpoints.add(breakpoint, hook)
+}
+
+func (b *breakpoints) add(bp Breakpoint, hook func(event EventInfo)) (remove func(), err error) {
+	registered := &breakpoint{
+		Breakpoint: bp,
+		hook:       hook,
+	}
+
+	if bp.Selector != "" {
+		if registered.expression, err = selector.Parse(string(bp.Selector)); err != nil {
+			return nil, err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.breakpoints = append(b.breakpoints, registered)
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.breakpoints = slices.DeleteFunc(b.breakpoints, func(other *breakpoint) bool { return other == registered })
+	}, nil
+}
+
+// matching returns the hooks of the breakpoint s selecting event, to be
+// called before and after its action.
+func (b *breakpoints) matching(event *events.Event) (before, after []func(event EventInfo)) {
+	if b == nil || event.Resumption() {
+		return nil, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, registered := range b.breakpoints {
+		if !registered.Time.IsZero() && !registered.Time.Equal(event.Time) {
+			continue
+		}
+
+		if registered.expression != nil && !registered.expression.Matches(selector.Set(event.Tags())) {
+			continue
+		}
+
+		if registered.After {
+			after = append(after, registered.hook)
+		} else {
+			before = append(before, registered.hook)
+		}
+	}
+
+	return before, after
+}
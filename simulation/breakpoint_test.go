@@ -0,0 +1,107 @@
+package simulation
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Break(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("before and after", func(t *testing.T) {
+		t.Parallel()
+
+		var runs atomic.Int32
+		s := NewScheduler(now)
+		s.PerformRepeatedly(context.Background(), timestone.SimpleAction(func(context.Context) {
+			runs.Add(1)
+		}), nil, time.Minute, "job")
+		s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) {}), 2*time.Minute, "other")
+
+		var before, after []int32
+		_, err := s.Break(Breakpoint{Selector: "job", Time: now.Add(2 * time.Minute)}, func(event EventInfo) {
+			require.Equal(t, EventInfo{Tags: []string{"job"}, Time: now.Add(2 * time.Minute)}, event)
+			require.Equal(t, now.Add(2*time.Minute), s.Now())
+			before = append(before, runs.Load())
+		})
+		require.NoError(t, err)
+		_, err = s.Break(Breakpoint{Selector: "job", After: true}, func(EventInfo) {
+			after = append(after, runs.Load())
+		})
+		require.NoError(t, err)
+
+		s.Forward(3 * time.Minute)
+
+		require.Equal(t, []int32{1}, before)
+		require.Equal(t, []int32{1, 2, 3}, after)
+	})
+
+	t.Run("step hook", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewScheduler(now)
+		noop := timestone.SimpleAction(func(context.Context) {})
+		s.PerformAfter(context.Background(), noop, time.Minute, "a")
+		s.PerformAfter(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+			_ = timestone.Sleep(ctx, time.Minute)
+		}), 2*time.Minute, "b")
+
+		var steps []EventInfo
+		clear, err := s.Break(Breakpoint{}, func(event EventInfo) {
+			steps = append(steps, event)
+		})
+		require.NoError(t, err)
+
+		s.Forward(5 * time.Minute)
+		require.Equal(t, []EventInfo{
+			{Tags: []string{"a"}, Time: now.Add(time.Minute)},
+			{Tags: []string{"b"}, Time: now.Add(2 * time.Minute)},
+		}, steps)
+
+		clear()
+		s.PerformAfter(context.Background(), noop, time.Minute, "c")
+		s.Forward(time.Minute)
+		require.Len(t, steps, 2)
+	})
+
+	t.Run("mutating state", func(t *testing.T) {
+		t.Parallel()
+
+		var runs atomic.Int32
+		s := NewScheduler(now)
+		handle := s.PerformRepeatedlyWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {
+			runs.Add(1)
+		}), nil, time.Minute, "poll")
+
+		_, err := s.Break(Breakpoint{Selector: "poll", Time: now.Add(2 * time.Minute), After: true}, func(EventInfo) {
+			handle.Cancel()
+		})
+		require.NoError(t, err)
+
+		s.Forward(time.Hour)
+		require.Equal(t, int32(2), runs.Load())
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewScheduler(now)
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "job")
+
+		called := false
+		_, err := s.Break(Breakpoint{Selector: "job &&"}, func(EventInfo) {
+			called = true
+		})
+		require.Error(t, err)
+
+		s.Forward(time.Minute)
+		require.False(t, called)
+	})
+}
@@ -5,6 +5,7 @@ import "time"
 // Event is used to target one or multiple events.
 type Event interface {
 	GetTags() []string
+	GetSelector() Selector
 }
 
 // All is a Event targeting all events with Tags.
@@ -12,36 +13,51 @@ type All struct {
 	// Tags to address events. An event will match if it has been at least
 	// tagged with all entries in Tags.
 	Tags []string
+	// Selector is optional and further restricts the events to address.
+	Selector Selector
 }
 
-func (a All) GetTags() []string { return a.Tags }
+func (a All) GetTags() []string     { return a.Tags }
+func (a All) GetSelector() Selector { return a.Selector }
 
 // At is a key that will target at Time by Tags.
 type At struct {
 	// Time is used to match only actions at the specific time. If you want
 	// to match all actions with the given Tags, pass nil for Time.
 	//
-	// If no matching event to wait for is found the scheduler will panic.
+	// If no matching event to wait for is found, the scheduler doesn't
+	// wait and reports it from ValidateConfigs.
 	Time time.Time
 	// Tags to address events. An event will match if it has been at least
 	// tagged with all entries in Tags.
 	Tags []string
+	// Selector is optional and further restricts the events to address.
+	Selector Selector
 }
 
-func (a At) GetTags() []string { return a.Tags }
+func (a At) GetTags() []string     { return a.Tags }
+func (a At) GetSelector() Selector { return a.Selector }
 
 type Before struct {
 	// Before will match an event relative to the event that is configured.
 	//
-	// Unlike At, where a missing match will result in a panic, a missing
+	// Unlike At, where a missing match will be reported, a missing
 	// match from the Before Event will be silently ignored
+	//
+	// Interval is meant to be negative to address earlier events, or zero
+	// to address simultaneous ones, which a Config without Time can't
+	// address with At. A positive Interval is reported as invalid, as
+	// the events it addresses are executed later.
 	Interval time.Duration
 	// Tags to address events. An event will match if it has been at least
 	// tagged with all entries in Tags.
 	Tags []string
+	// Selector is optional and further restricts the events to address.
+	Selector Selector
 }
 
-func (r Before) GetTags() []string { return r.Tags }
+func (r Before) GetTags() []string     { return r.Tags }
+func (r Before) GetSelector() Selector { return r.Selector }
 
 // Generator represents an expectation  for a number of
 // event generators to be added to a simulation.Scheduler. It will block
@@ -57,8 +73,14 @@ type Generator struct {
 // being scheduled and executed in the simulation.Scheduler.
 type Config struct {
 	// Tags to address events to configure. An event will match if it has
-	// been at least tagged with all entries in Tags.
+	// been at least tagged with all entries in Tags. Of multiple matching
+	// Config s, the one with the most Tags applies.
 	Tags []string
+	// Selector is optional and further restricts the events to configure.
+	// Tags may be left empty if Selector is set. Each tag or prefix the
+	// Selector refers to counts like an entry of Tags when determining the
+	// Config that applies.
+	Selector Selector
 	// Time is optional. If set, the Config will match specifically events
 	// at the given Time.
 	Time time.Time
@@ -77,4 +99,9 @@ type Config struct {
 	// number of corresponding newMatching event generators the Scheduler will
 	// expect to hold before continuing.
 	Adds []*Generator
+	// Duration is optional and models how long it takes to perform the
+	// action. The run loop considers the action as running until the
+	// Scheduler has been forwarded to its completion, and events waiting
+	// for it via WaitFor will only start at that time.
+	Duration Duration
 }
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Duration models how long it takes to perform an action, as set for
+// Config.Duration.
+type Duration interface {
+	// Sample returns the duration of the next execution.
+	Sample() time.Duration
+}
+
+// Fixed is a Duration that is the same for every execution.
+type Fixed time.Duration
+
+func (f Fixed) Sample() time.Duration { return time.Duration(f) }
+
+// Uniform is a Duration distributed uniformly between Min and Max. Its
+// samples are drawn from a random generator initialized with Seed, in
+// order to reproduce them in every run.
+type Uniform struct {
+	Min, Max time.Duration
+	Seed     uint64
+
+	seeded seeded
+}
+
+func (u *Uniform) Sample() time.Duration {
+	if u.Max <= u.Min {
+		return u.Min
+	}
+
+	var sample int64
+	u.seeded.draw(u.Seed, func(r *rand.Rand) { sample = r.Int64N(int64(u.Max - u.Min)) })
+
+	return u.Min + time.Duration(sample)
+}
+
+// Normal is a Duration with a normal distribution around Mean. Samples
+// are drawn from a random generator initialized with Seed like for
+// Uniform, and capped at zero.
+type Normal struct {
+	Mean, StdDev time.Duration
+	Seed         uint64
+
+	seeded seeded
+}
+
+func (n *Normal) Sample() time.Duration {
+	var sample float64
+	n.seeded.draw(n.Seed, func(r *rand.Rand) { sample = float64(n.Mean) + r.NormFloat64()*float64(n.StdDev) })
+
+	return time.Duration(math.Max(0, math.Round(sample)))
+}
+
+// seeded lazily initializes a random generator with a seed.
+type seeded struct {
+	r  *rand.Rand
+	mu sync.Mutex
+}
+
+func (s *seeded) draw(seed uint64, f func(r *rand.Rand)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.r == nil {
+		s.r = rand.New(rand.NewPCG(seed, seed))
+	}
+
+	f(s.r)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixed_Sample(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, time.Minute, Fixed(time.Minute).Sample())
+}
+
+func TestUniform_Sample(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		min, max time.Duration
+	}{
+		{
+			name: "range",
+			min:  time.Second,
+			max:  time.Minute,
+		},
+		{
+			name: "empty range",
+			min:  time.Minute,
+			max:  time.Minute,
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u1 := &Uniform{Min: tt.min, Max: tt.max, Seed: 42}
+			u2 := &Uniform{Min: tt.min, Max: tt.max, Seed: 42}
+
+			for i := 0; i < 100; i++ {
+				sample := u1.Sample()
+				require.Equal(t, sample, u2.Sample())
+				require.GreaterOrEqual(t, sample, tt.min)
+				require.LessOrEqual(t, sample, tt.max)
+			}
+		})
+	}
+}
+
+func TestNormal_Sample(t *testing.T) {
+	t.Parallel()
+
+	n1 := &Normal{Mean: time.Second, StdDev: time.Second, Seed: 42}
+	n2 := &Normal{Mean: time.Second, StdDev: time.Second, Seed: 42}
+	other := &Normal{Mean: time.Second, StdDev: time.Second, Seed: 7}
+
+	var samples, otherSamples []time.Duration
+	for i := 0; i < 100; i++ {
+		sample := n1.Sample()
+		require.Equal(t, sample, n2.Sample())
+		require.GreaterOrEqual(t, sample, time.Duration(0))
+
+		samples = append(samples, sample)
+		otherSamples = append(otherSamples, other.Sample())
+	}
+
+	require.NotEqual(t, samples, otherSamples)
+}
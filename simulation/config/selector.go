@@ -0,0 +1,19 @@
+package config
+
+// Selector is an expression to address events by their tags, as an
+// alternative to enumerating all combinations of tags in separate
+// Config s. It is made of
+//
+//   - a tag like "fooProcessing", selecting events tagged with it,
+//   - a prefix followed by a wildcard like "job:*", selecting events with
+//     a tag starting with "job:",
+//   - a label like "tenant=a", which is simply a tag, or "tenant!=a",
+//     selecting events not tagged "tenant=a" like a Prometheus selector,
+//
+// combined with "!", "&&" and "||" in descending order of precedence, and
+// grouped with parentheses, e.g.
+//
+//	"job:* && tenant=a && !(shard=1 || shard=2)"
+//
+// An invalid Selector will make the simulation.Scheduler panic.
+type Selector string
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProblemKind classifies a Problem found when validating Config s.
+type ProblemKind int
+
+const (
+	// ProblemWaitForCycle is reported for Config s whose WaitFor refer to
+	// each other, so that simultaneous events configured by them wait for
+	// each other forever.
+	ProblemWaitForCycle ProblemKind = iota + 1
+	// ProblemUnreachableAt is reported for an At in WaitFor whose event
+	// can't have been executed when the configured event waits for it.
+	ProblemUnreachableAt
+	// ProblemPositiveBefore is reported for a Before in WaitFor whose
+	// Interval is positive, so that it refers to events executed after
+	// the configured event. A Before without Interval is valid, as it
+	// refers to simultaneous events, and is checked for cycles like All
+	// and At.
+	ProblemPositiveBefore
+	// ProblemPriorityConflict is reported for a Config waiting for
+	// simultaneous events that have a greater Priority, and are therefore
+	// executed after the configured event.
+	ProblemPriorityConflict
+	// ProblemUnmatched is reported for a Config that hasn't configured any
+	// event.
+	ProblemUnmatched
+	// ProblemMissingTarget is reported for an All or At in WaitFor that
+	// no event existed for when an event configured by the Config waited
+	// for it, so that it didn't wait at all.
+	ProblemMissingTarget
+	// ProblemAmbiguous is reported for Config s that are equally specific
+	// for an event, if the Scheduler has been created with StrictConfigs.
+	ProblemAmbiguous
+	// ProblemInvalidSelector is reported for a Config whose Selector, or
+	// the Selector of one of its WaitFor, can't be parsed. The Config
+	// isn't configured.
+	ProblemInvalidSelector
+)
+
+func (k ProblemKind) String() string {
+	switch k {
+	case ProblemWaitForCycle:
+		return "WaitFor cycle"
+	case ProblemUnreachableAt:
+		return "unreachable At"
+	case ProblemPositiveBefore:
+		return "positive Before"
+	case ProblemPriorityConflict:
+		return "priority conflict"
+	case ProblemUnmatched:
+		return "unmatched"
+	case ProblemMissingTarget:
+		return "missing target"
+	case ProblemAmbiguous:
+		return "ambiguous"
+	case ProblemInvalidSelector:
+		return "invalid selector"
+	default:
+		return fmt.Sprintf("ProblemKind(%d)", int(k))
+	}
+}
+
+// Problem is a misconfiguration of one or multiple Config s.
+type Problem struct {
+	Kind ProblemKind
+	// Configs are the Config s involved.
+	Configs []Config
+	// Explanation describes the problem.
+	Explanation string
+}
+
+// ValidationError holds the Problem s found when validating Config s.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d invalid config(s):", len(e.Problems))
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&b, "\n\t%s: %s", problem.Kind, problem.Explanation)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	ctx, cancel := timestone.WithTimeout(context.Background(), s, time.Minute, "timeout")
+	defer cancel()
+
+	childCtx, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+
+	afterFuncCalled := false
+	timestone.AfterFunc(ctx, func() { afterFuncCalled = true })
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.Equal(t, now.Add(time.Minute), deadline)
+
+	s.Forward(59 * time.Second)
+	require.NoError(t, ctx.Err())
+	require.False(t, afterFuncCalled)
+
+	s.Forward(time.Second)
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	require.ErrorIs(t, context.Cause(ctx), context.DeadlineExceeded)
+	require.ErrorIs(t, childCtx.Err(), context.DeadlineExceeded)
+	require.True(t, afterFuncCalled)
+}
+
+func TestWithDeadline_order(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		config    config.Config
+		wantError error
+	}{
+		{
+			name: "request after expiry",
+			config: config.Config{
+				Tags:    []string{"request"},
+				WaitFor: []config.Event{config.All{Tags: []string{"timeout"}}},
+			},
+			wantError: context.DeadlineExceeded,
+		},
+		{
+			name: "request before expiry",
+			config: config.Config{
+				Tags:    []string{"timeout"},
+				WaitFor: []config.Event{config.All{Tags: []string{"request"}}},
+			},
+			wantError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewScheduler(now)
+			s.ConfigureEvents(tt.config)
+
+			ctx, cancel := timestone.WithDeadline(context.Background(), s, now.Add(time.Minute), "timeout")
+			defer cancel()
+
+			var requestErr error
+			s.PerformAfter(ctx, timestone.SimpleAction(func(ctx context.Context) {
+				requestErr = ctx.Err()
+			}), time.Minute, "request")
+
+			s.Forward(time.Minute)
+
+			require.Equal(t, tt.wantError, requestErr)
+		})
+	}
+}
+
+func TestWithDeadline_waitingAction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	ctx, cancel := timestone.WithTimeout(context.Background(), s, time.Hour, "timeout")
+	defer cancel()
+
+	var waitErr error
+	s.PerformNow(ctx, timestone.SimpleAction(func(ctx context.Context) {
+		<-ctx.Done()
+		waitErr = ctx.Err()
+	}), "waiting")
+
+	s.Forward(2 * time.Hour)
+
+	require.ErrorIs(t, waitErr, context.DeadlineExceeded)
+}
+
+func TestWithDeadline_cancelled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	ctx, cancel := timestone.WithDeadline(context.Background(), s, now.Add(time.Minute), "timeout")
+	cancel()
+
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	require.True(t, s.eventQueue.Finished())
+
+	s.Forward(time.Hour)
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestWithDeadline_parentCancelled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	parent, cancelParent := timestone.WithTimeout(context.Background(), s, time.Hour, "parent")
+	ctx, cancel := timestone.WithTimeout(parent, s, 2*time.Hour, "child")
+	defer cancel()
+
+	// The child doesn't outlive the earlier deadline of its parent.
+	deadline, _ := ctx.Deadline()
+	require.Equal(t, now.Add(time.Hour), deadline)
+
+	ctx, cancel = timestone.WithTimeout(parent, s, time.Minute, "child")
+	defer cancel()
+
+	cancelParent()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestWithDeadline_passed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	ctx, cancel := timestone.WithDeadline(context.Background(), s, now, "timeout")
+	defer cancel()
+
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	require.True(t, s.eventQueue.Finished())
+}
@@ -0,0 +1,38 @@
+package simulation
+
+import "github.com/metamogul/timestone/v2/simulation/internal/events"
+
+// decisions draws the decisions of a Scheduler created with Decisions
+// from a byte stream. It is only used by the run loop.
+type decisions struct {
+	data []byte
+}
+
+// next consumes the next byte of the stream, or returns 0 once it has
+// been exhausted.
+func (d *decisions) next() byte {
+	if len(d.data) == 0 {
+		return 0
+	}
+
+	b := d.data[0]
+	d.data = d.data[1:]
+
+	return b
+}
+
+// Choose implements events.Chooser, consuming a byte if there is a
+// choice.
+func (d *decisions) Choose(candidates []events.Event) int {
+	if len(candidates) < 2 {
+		return 0
+	}
+
+	return int(d.next()) % len(candidates)
+}
+
+// awaitIdle decides whether the run loop waits for an Event to be idle,
+// as with RunUntilIdle, before executing the next one.
+func (d *decisions) awaitIdle() bool {
+	return d.next()&1 == 0
+}
@@ -0,0 +1,264 @@
+package simulation
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+)
+
+// execution is passed as timestone.Clock to the action performed for an
+// Event. It allows the action to be parked by timestone.After and
+// timestone.Sleep until the Scheduler has been forwarded far enough, with
+// the run loop treating it like it has finished in the meantime.
+type execution struct {
+	scheduler *Scheduler
+	tags      []string
+
+	now time.Time
+	// duration is the remaining time it takes to perform the action, as
+	// sampled from Config.Duration.
+	duration time.Duration
+	// yield releases the run loop from the Event the action is currently
+	// performed for, reporting when the action has completed. It is nil
+	// while the action is parked.
+	yield       func(completedAt time.Time)
+	resumptions []*resumption
+	finished    bool
+
+	// traceEvent records the Event the execution has been created for, if
+	// the Scheduler records a Trace.
+	traceEvent *TraceEvent
+
+	// mu is locked before the eventGeneratorsMu of the scheduler, which
+	// interrupt acquires by resetting a resumption while holding mu. It
+	// must therefore never be locked while holding eventGeneratorsMu.
+	mu sync.Mutex
+}
+
+// resumption is the Event scheduled to resume a parked action.
+type resumption struct {
+	timestone.Handle
+
+	stopAfterFunc func() bool
+	resumed       bool
+}
+
+func newExecution(scheduler *Scheduler, event *events.Event, duration time.Duration, yield func(completedAt time.Time)) *execution {
+	return &execution{
+		scheduler: scheduler,
+		tags:      event.Tags(),
+		now:       event.Time,
+		duration:  duration,
+		yield:     yield,
+	}
+}
+
+// executionOf returns the execution of scheduler carried by ctx, if ctx
+// has been passed to an action performed by scheduler or derived from
+// such a context.Context.
+func executionOf(ctx context.Context, scheduler *Scheduler) (*execution, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	execution, ok := ctx.Value(timestone.ActionContextClockKey).(*execution)
+	if !ok || execution.scheduler != scheduler {
+		return nil, false
+	}
+
+	return execution, true
+}
+
+// Now implements timestone.Clock.
+func (e *execution) Now() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.now
+}
+
+// After parks the action and adds an Event to resume it after duration,
+// or as soon as ctx is done.
+func (e *execution) After(ctx context.Context, duration time.Duration) <-chan time.Time {
+	return e.park(ctx, duration, false)
+}
+
+// startAt parks the action until at, if it is to start later than the
+// time of its Event, e.g. because the events it waits for take time.
+func (e *execution) startAt(at time.Time) {
+	if duration := at.Sub(e.Now()); duration > 0 {
+		<-e.park(context.Background(), duration, true)
+	}
+}
+
+// elapse parks the action once it has returned until its duration has
+// elapsed, in order to model the time it takes to perform it.
+func (e *execution) elapse() {
+	e.mu.Lock()
+	duration := e.duration
+	e.duration = 0
+	e.mu.Unlock()
+
+	if duration > 0 {
+		<-e.park(context.Background(), duration, true)
+	}
+}
+
+// park parks the action and adds an Event to resume it after duration,
+// or as soon as ctx is done. If busy, the action isn't considered as
+// completed before it is resumed.
+func (e *execution) park(ctx context.Context, duration time.Duration, busy bool) <-chan time.Time {
+	c := make(chan time.Time, 1)
+
+	now := e.Now()
+	if duration <= 0 {
+		c <- now
+		return c
+	}
+
+	if ctx.Err() != nil {
+		return c
+	}
+
+	// Don't let the Event be cancelled along with ctx, as the action stops
+	// waiting for c once ctx is done and must be resumed right away for
+	// the run loop to keep track of it.
+	resumptionCtx := context.WithoutCancel(ctx)
+	r := &resumption{}
+	r.Handle = e.scheduler.add(resumptionCtx, events.NewResumptionGenerator(
+		resumptionCtx,
+		timestone.SimpleAction(func(resumptionCtx context.Context) {
+			e.resume(ctx, r, resumptionCtx.Value(timestone.ActionContextClockKey).(*execution), c, busy)
+		}),
+		now.Add(duration),
+		e.tags,
+	), e.tags, nil)
+	r.stopAfterFunc = timestone.AfterFunc(ctx, func() { e.interrupt(r) })
+
+	if ctx.Err() != nil {
+		r.stopAfterFunc()
+		r.Cancel()
+		return c
+	}
+
+	e.mu.Lock()
+	e.resumptions = slices.DeleteFunc(e.resumptions, func(r *resumption) bool {
+		if r.Status() != timestone.StatusFinished {
+			return false
+		}
+
+		r.stopAfterFunc()
+		return true
+	})
+	e.resumptions = append(e.resumptions, r)
+	yield := e.yield
+	e.yield = nil
+	e.mu.Unlock()
+
+	if yield != nil {
+		completedAt := now
+		if busy {
+			completedAt = now.Add(duration)
+		}
+
+		yield(completedAt)
+	}
+
+	return c
+}
+
+// resume is performed for the Event of r with resumption as its
+// execution. It blocks the Event until the action has been parked again
+// or has finished.
+//
+// Unless the action has been parked as busy, it is resumed no earlier
+// than the current time of the Scheduler. The Event might have been
+// added only after the clock has passed it, if the run loop hasn't been
+// configured to wait for the action to be parked, and the action mustn't
+// observe its clock going back.
+func (e *execution) resume(ctx context.Context, r *resumption, resumption *execution, c chan<- time.Time, busy bool) {
+	if !busy {
+		resumption.advance(e.scheduler.clock.Now())
+	}
+	at := resumption.Now()
+
+	e.mu.Lock()
+
+	if r.resumed {
+		e.mu.Unlock()
+		return
+	}
+	r.resumed = true
+
+	// Another Event is performing the action already, e.g. because it is
+	// waiting for multiple channels returned by After.
+	if e.finished || e.yield != nil {
+		e.mu.Unlock()
+		c <- at
+		return
+	}
+
+	resumed := make(chan time.Time, 1)
+	e.yield = func(completedAt time.Time) { resumed <- completedAt }
+
+	if at.After(e.now) {
+		e.now = at
+	}
+
+	e.mu.Unlock()
+
+	if ctx.Err() == nil {
+		c <- at
+	}
+
+	resumption.advance(<-resumed)
+}
+
+// interrupt reschedules the Event of r to resume the action immediately.
+// It keeps e.mu locked while resetting r, so that r can't be resumed in
+// the meantime and be rescheduled once more.
+func (e *execution) interrupt(r *resumption) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !r.resumed && r.Status() == timestone.StatusScheduled {
+		r.Reset(0)
+	}
+}
+
+// advance sets the clock of the execution to to, unless it is later
+// already.
+func (e *execution) advance(to time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if to.After(e.now) {
+		e.now = to
+	}
+}
+
+// finish releases the run loop once the action has returned and cancels
+// the Event s that would otherwise resume it.
+func (e *execution) finish() {
+	e.mu.Lock()
+	e.finished = true
+	yield := e.yield
+	e.yield = nil
+	resumptions := e.resumptions
+	e.resumptions = nil
+	now := e.now
+	e.mu.Unlock()
+
+	for _, r := range resumptions {
+		r.stopAfterFunc()
+		r.Cancel()
+	}
+
+	if yield != nil {
+		yield(now)
+	}
+}
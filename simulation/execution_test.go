@@ -0,0 +1,298 @@
+package simulation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecution_Sleep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	s.ConfigureEvents(config.Config{
+		Tags: []string{"retrying"},
+		Adds: []*config.Generator{{Tags: []string{"retrying"}, Count: 1}},
+	})
+
+	// A retry loop which can't be split into multiple actions.
+	handle := s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		for attempt := 0; attempt < 3; attempt++ {
+			recorder.action().Perform(ctx)
+			require.NoError(t, timestone.Sleep(ctx, time.Minute))
+		}
+	}), "retrying")
+
+	s.Forward(90 * time.Second)
+	require.Equal(t, []time.Time{now, now.Add(time.Minute)}, recorder.times())
+	require.Equal(t, timestone.StatusRunning, handle.Status())
+
+	s.Forward(time.Hour)
+	require.Equal(t, []time.Time{now, now.Add(time.Minute), now.Add(2 * time.Minute)}, recorder.times())
+	require.Equal(t, timestone.StatusFinished, handle.Status())
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestExecution_Sleep_order(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	s.ConfigureEvents(
+		config.Config{
+			Tags:     []string{"sleeping"},
+			Priority: 1,
+			Adds:     []*config.Generator{{Tags: []string{"sleeping"}, Count: 1}},
+		},
+		config.Config{
+			Tags:     []string{"other"},
+			Priority: 2,
+			WaitFor:  []config.Event{config.All{Tags: []string{"sleeping"}}},
+		},
+	)
+
+	var order []string
+	var mu sync.Mutex
+	record := func(step string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		order = append(order, step)
+	}
+
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		record("before sleep")
+		_ = timestone.Sleep(ctx, time.Minute)
+		record("after sleep")
+	}), "sleeping")
+	s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) {
+		record("other")
+	}), time.Minute, "other")
+
+	s.Forward(time.Minute)
+
+	require.Equal(t, []string{"before sleep", "after sleep", "other"}, order)
+}
+
+func TestExecution_Sleep_resumedLate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	released := make(chan struct{})
+	var resumedAt time.Time
+
+	handle := s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		<-released
+		_ = timestone.Sleep(ctx, time.Minute)
+		resumedAt = ctx.Value(timestone.ActionContextClockKey).(timestone.Clock).Now()
+	}), "sleeping")
+	s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) {
+		close(released)
+	}), 5*time.Minute, "releasing")
+
+	// The action only parks once the clock has passed the time it is to
+	// be resumed at.
+	s.Forward(10 * time.Minute)
+	<-handle.Done()
+
+	// Depending on when the action parks, it is resumed at the time of
+	// the releasing action or at the end of Forward, but not in the past.
+	require.False(t, resumedAt.Before(now.Add(5*time.Minute)), "resumed at %v", resumedAt)
+}
+
+func TestExecution_Sleep_contextCancelled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	s.ConfigureEvents(config.Config{
+		Tags: []string{"sleeping"},
+		Adds: []*config.Generator{{Tags: []string{"sleeping"}, Count: 1}},
+	})
+
+	ctx, cancel := timestone.WithTimeout(context.Background(), s, time.Minute, "timeout")
+	defer cancel()
+
+	var sleepErr error
+	s.PerformNow(ctx, timestone.SimpleAction(func(ctx context.Context) {
+		sleepErr = timestone.Sleep(ctx, time.Hour)
+	}), "sleeping")
+
+	s.Forward(2 * time.Minute)
+
+	require.ErrorIs(t, sleepErr, context.DeadlineExceeded)
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestExecution_After_notReceived(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	handle := s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		timestone.After(ctx, time.Minute)
+	}), "abandoning")
+
+	s.Forward(time.Hour)
+
+	require.Equal(t, timestone.StatusFinished, handle.Status())
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestExecution_After_select(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	s.ConfigureEvents(config.Config{
+		Tags:     []string{"polling"},
+		Priority: 1,
+		WaitFor:  []config.Event{config.Before{Tags: []string{"timeout"}}},
+		Adds:     []*config.Generator{{Tags: []string{"polling"}, Count: 1}},
+	})
+
+	ctx, cancel := timestone.WithTimeout(context.Background(), s, 2*time.Minute, "timeout")
+	defer cancel()
+
+	handle := s.PerformNowWithHandle(ctx, timestone.SimpleAction(func(ctx context.Context) {
+		for {
+			recorder.action().Perform(ctx)
+
+			select {
+			case <-timestone.After(ctx, time.Minute):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}), "polling")
+
+	s.Forward(time.Hour)
+
+	require.Equal(t, []time.Time{now, now.Add(time.Minute)}, recorder.times())
+	require.Equal(t, timestone.StatusCancelled, handle.Status())
+	<-handle.Done()
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestExecution_After_zero(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	var wokenAt time.Time
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		wokenAt = <-timestone.After(ctx, 0)
+	}), "waiting")
+
+	s.Forward(time.Minute)
+
+	require.Equal(t, now, wokenAt)
+}
+
+func TestExecution_Duration(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	s.ConfigureEvents(
+		config.Config{
+			Tags:     []string{"batch"},
+			Duration: config.Fixed(10 * time.Minute),
+		},
+		config.Config{
+			Tags:    []string{"report"},
+			WaitFor: []config.Event{config.All{Tags: []string{"batch"}}},
+		},
+	)
+
+	batchHandle := s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {}), "batch")
+	reportHandle := s.PerformAfterWithHandle(context.Background(), recorder.action(), time.Minute, "report")
+
+	s.Forward(5 * time.Minute)
+	require.Equal(t, timestone.StatusRunning, batchHandle.Status())
+	require.Equal(t, timestone.StatusRunning, reportHandle.Status())
+	require.Empty(t, recorder.times())
+
+	s.Forward(5 * time.Minute)
+	require.Equal(t, timestone.StatusFinished, batchHandle.Status())
+	require.Equal(t, timestone.StatusFinished, reportHandle.Status())
+	require.Equal(t, []time.Time{now.Add(10 * time.Minute)}, recorder.times())
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestExecution_Duration_overlapping(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	s.ConfigureEvents(
+		config.Config{
+			Tags:     []string{"batch"},
+			Duration: config.Fixed(90 * time.Second),
+		},
+		config.Config{
+			Tags:    []string{"report"},
+			WaitFor: []config.Event{config.Before{Interval: -time.Minute, Tags: []string{"batch"}}},
+		},
+	)
+
+	s.PerformRepeatedly(context.Background(), timestone.SimpleAction(func(context.Context) {}), nil, time.Minute, "batch")
+	s.PerformRepeatedly(context.Background(), recorder.action(), nil, time.Minute, "report")
+
+	s.Forward(3*time.Minute + 30*time.Second)
+
+	// Each report waits for the batch job started a minute before it, which
+	// takes longer than that.
+	require.Equal(t, []time.Time{
+		now.Add(time.Minute),
+		now.Add(time.Minute + 90*time.Second),
+		now.Add(2*time.Minute + 90*time.Second),
+	}, recorder.times())
+}
+
+func TestExecution_Duration_forwardOne(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	s.ConfigureEvents(config.Config{
+		Tags:     []string{"batch"},
+		Duration: config.Fixed(10 * time.Minute),
+	})
+
+	handle := s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {}), "batch")
+
+	s.ForwardOne()
+	s.Wait()
+	require.Equal(t, timestone.StatusRunning, handle.Status())
+	require.Equal(t, now.Add(10*time.Minute), s.eventQueue.Peek().Time)
+
+	s.ForwardOne()
+	s.Wait()
+	require.Equal(t, timestone.StatusFinished, handle.Status())
+	require.Equal(t, now.Add(10*time.Minute), s.Now())
+}
@@ -0,0 +1,212 @@
+package simulation
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2/simulation/internal/selector"
+)
+
+// The Expect... functions assert on the Event s executed by a Scheduler
+// created with RecordTrace. Event s resuming parked actions are considered
+// part of the Event whose action they resume. Event s are addressed by
+// config.Selector s, so that a single tag addresses all Event s tagged
+// with it. On failure, the Trace is logged.
+
+// ExpectOrder asserts that Event s matching each of selectors have been
+// released, and that all of those matching one selector have been
+// released before the first one matching the next selector.
+// ExpectOrder(t, s, "foo", "bar") is satisfied by Event s tagged foo,
+// foo, bar, bar, but not by foo, bar, foo, bar. To assert on the order of
+// repeated Event s, call it after forwarding through the first of them.
+func ExpectOrder(t testing.TB, s *Scheduler, selectors ...string) bool {
+	t.Helper()
+
+	traceEvents, expressions, ok := expectPrepare(t, s, selectors...)
+	if !ok {
+		return false
+	}
+
+	got := make([][]int, len(selectors))
+	for i, event := range traceEvents {
+		for j, expression := range expressions {
+			if expression.Matches(selector.Set(event.Tags)) {
+				got[j] = append(got[j], i)
+			}
+		}
+	}
+
+	inOrder := true
+	for i, released := range got {
+		inOrder = inOrder && len(released) > 0 && (i == 0 || slices.Max(got[i-1]) < released[0])
+	}
+
+	if !inOrder {
+		var gotTags [][]string
+		for _, event := range traceEvents {
+			if slices.ContainsFunc(expressions, func(e selector.Expression) bool { return e.Matches(selector.Set(event.Tags)) }) {
+				gotTags = append(gotTags, event.Tags)
+			}
+		}
+
+		return expectFail(t, s, "Expected Event s in order %q, got %q", selectors, gotTags)
+	}
+
+	return true
+}
+
+// ExpectRanAt asserts that the Event s matching sel have been scheduled
+// exactly for times, in any order.
+func ExpectRanAt(t testing.TB, s *Scheduler, sel string, times ...time.Time) bool {
+	t.Helper()
+
+	traceEvents, expressions, ok := expectPrepare(t, s, sel)
+	if !ok {
+		return false
+	}
+
+	var got []time.Time
+	for _, event := range traceEvents {
+		if expressions[0].Matches(selector.Set(event.Tags)) {
+			got = append(got, event.Time)
+		}
+	}
+	slices.SortFunc(got, time.Time.Compare)
+	times = slices.SortedFunc(slices.Values(times), time.Time.Compare)
+
+	if !slices.EqualFunc(got, times, time.Time.Equal) {
+		return expectFail(t, s, "Expected Event s %q at %v, got %v", sel, times, got)
+	}
+
+	return true
+}
+
+// ExpectCount asserts that n Event s matching sel have been executed.
+func ExpectCount(t testing.TB, s *Scheduler, sel string, n int) bool {
+	t.Helper()
+
+	traceEvents, expressions, ok := expectPrepare(t, s, sel)
+	if !ok {
+		return false
+	}
+
+	got := 0
+	for _, event := range traceEvents {
+		if expressions[0].Matches(selector.Set(event.Tags)) {
+			got++
+		}
+	}
+
+	if got != n {
+		return expectFail(t, s, "Expected %d Event s %q, got %d", n, sel, got)
+	}
+
+	return true
+}
+
+// ExpectNotRan asserts that no Event matching sel has been executed.
+func ExpectNotRan(t testing.TB, s *Scheduler, sel string) bool {
+	t.Helper()
+
+	return ExpectCount(t, s, sel, 0)
+}
+
+// ExpectConcurrent asserts that an Event matching a and one matching b
+// have overlapped in the time of the Scheduler's clock, from their
+// release until the completion of their actions.
+func ExpectConcurrent(t testing.TB, s *Scheduler, a, b string) bool {
+	t.Helper()
+
+	traceEvents, expressions, ok := expectPrepare(t, s, a, b)
+	if !ok {
+		return false
+	}
+
+	for _, eventA := range traceEvents {
+		if !expressions[0].Matches(selector.Set(eventA.Tags)) {
+			continue
+		}
+
+		for _, eventB := range traceEvents {
+			if eventA.releaseOrder == eventB.releaseOrder || !expressions[1].Matches(selector.Set(eventB.Tags)) {
+				continue
+			}
+
+			if !eventA.ReleasedAt.After(runsUntil(eventB)) && !eventB.ReleasedAt.After(runsUntil(eventA)) {
+				return true
+			}
+		}
+	}
+
+	return expectFail(t, s, "Expected Event s %q and %q to run concurrently", a, b)
+}
+
+// runsUntil returns when event has completed, or the maximum time if it
+// hasn't.
+func runsUntil(event TraceEvent) time.Time {
+	if event.CompletedAt.IsZero() {
+		return time.Unix(1<<62, 0)
+	}
+
+	return event.CompletedAt
+}
+
+// expectPrepare returns the released TraceEvent s of s in the order of
+// their release, with their completion extended by the Event s resuming
+// them, along with the parsed selectors.
+func expectPrepare(t testing.TB, s *Scheduler, selectors ...string) ([]TraceEvent, []selector.Expression, bool) {
+	t.Helper()
+
+	trace := s.Trace()
+	if trace == nil {
+		t.Error("The Scheduler must be created with RecordTrace to assert on the Event s it executed")
+		return nil, nil, false
+	}
+
+	expressions := make([]selector.Expression, len(selectors))
+	for i, sel := range selectors {
+		expression, err := selector.Parse(sel)
+		if err != nil {
+			t.Error(err)
+			return nil, nil, false
+		}
+		expressions[i] = expression
+	}
+
+	traceEvents := trace.Events()
+	for i, event := range traceEvents {
+		if j := resumedBy(traceEvents, i); j >= 0 && event.CompletedAt.After(traceEvents[j].CompletedAt) {
+			traceEvents[j].CompletedAt = event.CompletedAt
+		}
+	}
+
+	traceEvents = slices.DeleteFunc(traceEvents, func(event TraceEvent) bool {
+		return event.Resumption || event.releaseOrder == 0
+	})
+	slices.SortFunc(traceEvents, func(a, b TraceEvent) int {
+		if c := a.ReleasedAt.Compare(b.ReleasedAt); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.releaseOrder, b.releaseOrder)
+	})
+
+	return traceEvents, expressions, true
+}
+
+// expectFail fails t with a message formatted like fmt.Sprintf, followed
+// by the Trace of s.
+func expectFail(t testing.TB, s *Scheduler, format string, args ...any) bool {
+	t.Helper()
+
+	t.Errorf(format+"\n\nTrace:\n%s", append(args, indent(s.Trace().String()))...)
+
+	return false
+}
+
+func indent(text string) string {
+	return "\t" + strings.ReplaceAll(strings.TrimSuffix(text, "\n"), "\n", "\n\t")
+}
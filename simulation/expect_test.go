@@ -0,0 +1,168 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingT records whether an assertion has failed.
+type recordingT struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingT) Helper()               {}
+func (r *recordingT) Error(...any)          { r.failed = true }
+func (r *recordingT) Errorf(string, ...any) { r.failed = true }
+
+// newExpectScheduler returns a Scheduler that has executed an action
+// tagged job:one followed by one tagged job:two every minute for three
+// minutes, and an action tagged slow taking two minutes from the start.
+func newExpectScheduler(t *testing.T, options ...Option) *Scheduler {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now, options...)
+	s.ConfigureEvents(
+		config.Config{Tags: []string{"job:two"}, Priority: -1, WaitFor: []config.Event{config.Before{Tags: []string{"job:one"}}}},
+		config.Config{Tags: []string{"slow"}, Duration: config.Fixed(2 * time.Minute)},
+	)
+
+	until := now.Add(4 * time.Minute)
+	s.PerformRepeatedly(context.Background(), timestone.SimpleAction(func(context.Context) {}), &until, time.Minute, "job:one")
+	s.PerformRepeatedly(context.Background(), timestone.SimpleAction(func(context.Context) {}), &until, time.Minute, "job:two")
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "slow")
+
+	s.Forward(time.Hour)
+
+	return s
+}
+
+func TestExpect(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name       string
+		expect     func(t testing.TB, s *Scheduler) bool
+		wantFailed bool
+	}{
+		{
+			name: "order",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectOrder(t, s, "slow", "job:*")
+			},
+		},
+		{
+			name: "order reversed",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectOrder(t, s, "job:*", "slow")
+			},
+			wantFailed: true,
+		},
+		{
+			name: "order interleaved",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectOrder(t, s, "job:one", "job:two")
+			},
+			wantFailed: true,
+		},
+		{
+			name: "order not ran",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectOrder(t, s, "slow", "job:three")
+			},
+			wantFailed: true,
+		},
+		{
+			name: "ran at",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectRanAt(t, s, "job:one", now.Add(time.Minute), now.Add(2*time.Minute), now.Add(3*time.Minute))
+			},
+		},
+		{
+			name: "ran at, unsorted",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectRanAt(t, s, "job:one", now.Add(3*time.Minute), now.Add(time.Minute), now.Add(2*time.Minute))
+			},
+		},
+		{
+			name: "ran at, missing time",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectRanAt(t, s, "job:one", now.Add(time.Minute), now.Add(2*time.Minute))
+			},
+			wantFailed: true,
+		},
+		{
+			name: "count",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectCount(t, s, "job:*", 6)
+			},
+		},
+		{
+			name: "count mismatch",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectCount(t, s, "job:* && !job:two", 6)
+			},
+			wantFailed: true,
+		},
+		{
+			name: "not ran",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectNotRan(t, s, "job:three")
+			},
+		},
+		{
+			name: "not ran, but ran",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectNotRan(t, s, "slow")
+			},
+			wantFailed: true,
+		},
+		{
+			name: "concurrent",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectConcurrent(t, s, "slow", "job:two")
+			},
+		},
+		{
+			name: "not concurrent",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectConcurrent(t, s, "job:one", "job:one")
+			},
+			wantFailed: true,
+		},
+		{
+			name: "invalid selector",
+			expect: func(t testing.TB, s *Scheduler) bool {
+				return ExpectCount(t, s, "job:* &&", 6)
+			},
+			wantFailed: true,
+		},
+	}
+
+	s := newExpectScheduler(t, RecordTrace())
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &recordingT{TB: t}
+			require.Equal(t, !tt.wantFailed, tt.expect(recorder, s))
+			require.Equal(t, tt.wantFailed, recorder.failed)
+		})
+	}
+}
+
+func TestExpect_noTrace(t *testing.T) {
+	t.Parallel()
+
+	recorder := &recordingT{TB: t}
+	require.False(t, ExpectNotRan(recorder, newExpectScheduler(t), "slow"))
+	require.True(t, recorder.failed)
+}
@@ -0,0 +1,295 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2/simulation/config"
+	configinternal "github.com/metamogul/timestone/v2/simulation/internal/config"
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+)
+
+// EventStatus is the status of an Event described by an Explanation.
+type EventStatus int
+
+const (
+	// EventQueued is the status of an Event in the event queue that hasn't
+	// been executed yet.
+	EventQueued EventStatus = iota
+	// EventBlocked is the status of an Event whose action waits for the
+	// config.Config.WaitFor of its config.Config to complete.
+	EventBlocked
+	// EventRunning is the status of an Event whose action is performed and
+	// hasn't returned or been parked yet.
+	EventRunning
+	// EventCompleted is the status of Event s whose actions have returned
+	// or been parked.
+	EventCompleted
+)
+
+func (s EventStatus) String() string {
+	switch s {
+	case EventQueued:
+		return "queued"
+	case EventBlocked:
+		return "blocked"
+	case EventRunning:
+		return "running"
+	case EventCompleted:
+		return "completed"
+	default:
+		return fmt.Sprintf("EventStatus(%d)", int(s))
+	}
+}
+
+// Explanation describes the state of the Event s targeted by a
+// config.Event, as returned by Scheduler.Explain.
+type Explanation struct {
+	Target config.Event
+	// Err is set if the Selector of Target can't be parsed, which leaves
+	// Events empty.
+	Err error
+	// Events are the Event s Target refers to, ordered by time.
+	Events []EventExplanation
+	// Expected are the generators expected by config.Config.Adds that
+	// haven't been added yet, which block the run loop regardless of
+	// Target. If they can't be determined because generators are being
+	// added, ExpectedUnknown is true.
+	Expected        []ExpectedGenerators
+	ExpectedUnknown bool
+}
+
+// EventExplanation describes an Event, or the completed Event s with the
+// same tags at the same time.
+type EventExplanation struct {
+	Tags   []string
+	Time   time.Time
+	Status EventStatus
+	// Config is the config.Config applying to the Event, or nil if there
+	// is none.
+	Config *config.Config
+	// Unsatisfied are the config.Event s of config.Config.WaitFor the
+	// Event is blocked by, with config.Before converted to the time they
+	// refer to.
+	Unsatisfied []config.Event
+	// CompletedAt is the time of the Scheduler's clock at which the Event
+	// s have completed, if their Status is EventCompleted.
+	CompletedAt time.Time
+}
+
+// ExpectedGenerators is a number of generators with Tags expected by
+// config.Config.Adds that haven't been added yet.
+type ExpectedGenerators struct {
+	Tags  []string
+	Count int
+}
+
+// Explain describes the state of the Event s target refers to, to find
+// out why a test stalls: whether they are queued, blocked, running or
+// completed, which config.Config applies to them, which of its
+// config.Config.WaitFor they are blocked by, and which generators
+// expected by config.Config.Adds haven't been added yet. Only the next
+// Event of every generator is known to be queued. Explain doesn't block,
+// so it can be called while Forward is stalled.
+func (s *Scheduler) Explain(target config.Event) Explanation {
+	result := Explanation{Target: target}
+
+	parsedTarget, err := configinternal.ParseTarget(target)
+	if err != nil {
+		result.Err = err
+	} else {
+		result.Events = s.explainEvents(parsedTarget)
+	}
+
+	expectations, ok := s.eventQueue.NewGeneratorsWaitGroups.Pending()
+	result.ExpectedUnknown = !ok
+	for _, expectation := range expectations {
+		result.Expected = append(result.Expected, ExpectedGenerators(expectation))
+	}
+
+	return result
+}
+
+// explainEvents returns the explanations of the Event s target refers
+// to, ordered by time.
+func (s *Scheduler) explainEvents(target configinternal.Target) []EventExplanation {
+	var result []EventExplanation
+
+	s.eventGeneratorsMu.Lock()
+	queued := s.eventQueue.Queued(target)
+	s.eventGeneratorsMu.Unlock()
+
+	pending := s.pending.all()
+
+	for _, state := range s.eventWaitGroups.States(target) {
+		if state.Pending > 0 {
+			continue
+		}
+
+		result = append(result, EventExplanation{
+			Tags:        state.Tags,
+			Time:        state.Time,
+			Status:      EventCompleted,
+			Config:      s.eventConfigs.Applied(state.Time, state.Tags),
+			CompletedAt: state.CompletedAt,
+		})
+	}
+
+	for _, p := range pending {
+		if !events.Targets(target, p.event) {
+			continue
+		}
+
+		explanation := EventExplanation{
+			Tags:   p.event.Tags(),
+			Time:   p.event.Time,
+			Status: EventRunning,
+			Config: s.eventConfigs.Applied(p.event.Time, p.event.Tags()),
+		}
+
+		if !p.released {
+			explanation.Status = EventBlocked
+			for _, blockingEvent := range p.blockingEvents {
+				if !s.eventWaitGroups.Satisfied(blockingEvent) {
+					explanation.Unsatisfied = append(explanation.Unsatisfied, blockingEvent.Event)
+				}
+			}
+		}
+
+		result = append(result, explanation)
+	}
+
+	for _, event := range queued {
+		result = append(result, EventExplanation{
+			Tags:   event.Tags(),
+			Time:   event.Time,
+			Status: EventQueued,
+			Config: s.eventConfigs.Applied(event.Time, event.Tags()),
+		})
+	}
+
+	slices.SortStableFunc(result, func(a, b EventExplanation) int { return a.Time.Compare(b.Time) })
+
+	return result
+}
+
+// String returns a textual representation of the Explanation.
+func (e Explanation) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Event s targeted by %T%+v:\n", e.Target, e.Target)
+	if e.Err != nil {
+		fmt.Fprintf(&b, "\t%v\n", e.Err)
+	} else if len(e.Events) == 0 {
+		b.WriteString("\tnone\n")
+	}
+
+	for _, event := range e.Events {
+		fmt.Fprintf(&b, "\t%s at %v: %s", strings.Join(event.Tags, ","), event.Time, event.Status)
+		if event.Status == EventCompleted {
+			fmt.Fprintf(&b, " at %v", event.CompletedAt)
+		}
+		b.WriteString("\n")
+
+		if event.Config != nil {
+			fmt.Fprintf(&b, "\t\tconfigured by config tagged %v", event.Config.Tags)
+			if event.Config.Selector != "" {
+				fmt.Fprintf(&b, " selecting %q", event.Config.Selector)
+			}
+			if !event.Config.Time.IsZero() {
+				fmt.Fprintf(&b, " at %v", event.Config.Time)
+			}
+			b.WriteString("\n")
+		}
+
+		for _, blockingEvent := range event.Unsatisfied {
+			fmt.Fprintf(&b, "\t\twaiting for %T%+v\n", blockingEvent, blockingEvent)
+		}
+	}
+
+	switch {
+	case e.ExpectedUnknown:
+		b.WriteString("Expected generators: unknown, they are being added\n")
+	case len(e.Expected) > 0:
+		b.WriteString("Expected generators not added yet:\n")
+		for _, expected := range e.Expected {
+			fmt.Fprintf(&b, "\t%d tagged %s\n", expected.Count, strings.Join(expected.Tags, ","))
+		}
+	}
+
+	return b.String()
+}
+
+// pendingEvents holds the Event s executed by the run loop whose actions
+// haven't completed yet.
+type pendingEvents struct {
+	events []*pendingEvent
+	mu     sync.Mutex
+}
+
+// pendingEvent is an Event whose action hasn't completed yet.
+type pendingEvent struct {
+	event          *events.Event
+	blockingEvents []configinternal.Target
+	released       bool
+}
+
+// add records that event is executed, waiting for blockingEvents.
+func (p *pendingEvents) add(event *events.Event, blockingEvents []configinternal.Target) *pendingEvent {
+	if p == nil {
+		return nil
+	}
+
+	pending := &pendingEvent{event: event, blockingEvents: blockingEvents}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, pending)
+
+	return pending
+}
+
+// released records that the action of pending starts to be performed.
+func (p *pendingEvents) released(pending *pendingEvent) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pending.released = true
+}
+
+// completed records that the action of pending has completed.
+func (p *pendingEvents) completed(pending *pendingEvent) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = slices.DeleteFunc(p.events, func(e *pendingEvent) bool { return e == pending })
+}
+
+// all returns a copy of the pendingEvent s.
+func (p *pendingEvents) all() []pendingEvent {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make([]pendingEvent, len(p.events))
+	for i, pending := range p.events {
+		result[i] = *pending
+	}
+
+	return result
+}
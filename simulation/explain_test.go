@@ -0,0 +1,124 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Explain(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("blocked event", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewScheduler(now)
+		s.ConfigureEvents(config.Config{
+			Tags:    []string{"job", "waiting"},
+			WaitFor: []config.Event{config.All{Tags: []string{"stuck"}}, config.All{Tags: []string{"done"}}},
+		})
+
+		release := make(chan struct{})
+		noop := timestone.SimpleAction(func(context.Context) {})
+
+		s.PerformNow(context.Background(), noop, "job", "done")
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) { <-release }), "job", "stuck")
+		s.PerformNow(context.Background(), noop, "job", "waiting")
+		s.PerformAfter(context.Background(), noop, time.Hour, "job", "later")
+
+		forwarded := make(chan struct{})
+		go func() {
+			s.Forward(time.Minute)
+			close(forwarded)
+		}()
+
+		var explanation Explanation
+		require.Eventually(t, func() bool {
+			explanation = s.Explain(config.All{Tags: []string{"job"}})
+			return len(explanation.Events) == 4 && explanation.Events[2].Status == EventBlocked
+		}, time.Second, time.Millisecond)
+
+		require.Equal(t, []string{"job", "done"}, explanation.Events[0].Tags)
+		require.Equal(t, EventCompleted, explanation.Events[0].Status)
+		require.Equal(t, now, explanation.Events[0].CompletedAt)
+		require.Nil(t, explanation.Events[0].Config)
+
+		require.Equal(t, []string{"job", "stuck"}, explanation.Events[1].Tags)
+		require.Equal(t, EventRunning, explanation.Events[1].Status)
+
+		require.Equal(t, []string{"job", "waiting"}, explanation.Events[2].Tags)
+		require.Equal(t, []string{"job", "waiting"}, explanation.Events[2].Config.Tags)
+		require.Equal(t, []config.Event{config.All{Tags: []string{"stuck"}}}, explanation.Events[2].Unsatisfied)
+
+		require.Equal(t, []string{"job", "later"}, explanation.Events[3].Tags)
+		require.Equal(t, EventQueued, explanation.Events[3].Status)
+		require.Equal(t, now.Add(time.Hour), explanation.Events[3].Time)
+
+		require.Equal(t,
+			"Event s targeted by config.All{Tags:[job] Selector:}:\n"+
+				"\tjob,done at 2024-01-01 12:00:00 +0000 UTC: completed at 2024-01-01 12:00:00 +0000 UTC\n"+
+				"\tjob,stuck at 2024-01-01 12:00:00 +0000 UTC: running\n"+
+				"\tjob,waiting at 2024-01-01 12:00:00 +0000 UTC: blocked\n"+
+				"\t\tconfigured by config tagged [job waiting]\n"+
+				"\t\twaiting for config.All{Tags:[stuck] Selector:}\n"+
+				"\tjob,later at 2024-01-01 13:00:00 +0000 UTC: queued\n",
+			explanation.String(),
+		)
+
+		close(release)
+		<-forwarded
+
+		explanation = s.Explain(config.At{Time: now, Tags: []string{"waiting"}})
+		require.Len(t, explanation.Events, 1)
+		require.Equal(t, EventCompleted, explanation.Events[0].Status)
+	})
+
+	t.Run("expected generators", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewScheduler(now)
+		s.ConfigureEvents(config.Config{
+			Tags: []string{"parent"},
+			Adds: []*config.Generator{{Tags: []string{"child"}, Count: 2}},
+		})
+
+		noop := timestone.SimpleAction(func(context.Context) {})
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+			s.PerformNow(ctx, noop, "child")
+		}), "parent")
+
+		forwarded := make(chan struct{})
+		go func() {
+			s.Forward(time.Minute)
+			close(forwarded)
+		}()
+
+		require.Eventually(t, func() bool {
+			explanation := s.Explain(config.All{Tags: []string{"parent"}})
+			return len(explanation.Expected) == 1 && explanation.Expected[0].Count == 1
+		}, time.Second, time.Millisecond)
+		require.Equal(t, []ExpectedGenerators{{Tags: []string{"child"}, Count: 1}}, s.Explain(config.All{Tags: []string{"parent"}}).Expected)
+
+		s.PerformNow(context.Background(), noop, "child")
+		<-forwarded
+
+		require.Empty(t, s.Explain(config.All{Tags: []string{"parent"}}).Expected)
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewScheduler(now)
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "job")
+
+		explanation := s.Explain(config.All{Selector: "job &&"})
+		require.Error(t, explanation.Err)
+		require.Empty(t, explanation.Events)
+	})
+}
@@ -0,0 +1,186 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+)
+
+// maxExploredOrderings limits the number of orderings Explore runs a
+// scenario for, as it grows factorially with the number of simultaneous
+// Event s.
+const maxExploredOrderings = 10_000
+
+// Explore runs scenario in a subtest for every distinct order in which a
+// Scheduler created with now and options can execute simultaneous
+// Event s that aren't ordered by their config.Config.Priority or
+// config.Config.WaitFor. Orderings that only differ in the order of
+// Event s with the same tags are considered equivalent and run once.
+//
+// The Scheduler is created with RunUntilIdle, so that every Event is
+// executed on its own and the Event s its action adds are known before
+// the next one is chosen. scenario must use the Scheduler passed to it,
+// and be deterministic apart from the ordering explored. Explore fails
+// if a run deviates from the ordering it replays.
+//
+// For each ordering scenario fails for, Explore reports the config.Config
+// s reproducing it when passed to ConfigureEvents of a Scheduler created
+// with RunUntilIdle.
+func Explore(t *testing.T, now time.Time, scenario func(t *testing.T, s *Scheduler), options ...Option) {
+	t.Helper()
+
+	var prefix []int
+	for ordering := 1; ; ordering++ {
+		if ordering > maxExploredOrderings {
+			t.Fatalf("Explored more than %d orderings, restrict them with config.Config.Priority or config.Config.WaitFor", maxExploredOrderings)
+		}
+
+		s := NewScheduler(now, slices.Concat(options, []Option{RunUntilIdle()})...)
+		e := &explorer{prefix: prefix}
+		s.eventQueue.SetChooser(e)
+
+		passed := t.Run(fmt.Sprintf("ordering %d", ordering), func(t *testing.T) {
+			scenario(t, s)
+		})
+
+		if e.deviated {
+			t.Fatalf("Ordering %d deviated from ordering %d, the scenario isn't deterministic", ordering, ordering-1)
+		}
+
+		if !passed {
+			t.Errorf("Ordering %d failed, reproduce it with\n%s", ordering, formatConfigs(e.configs(s.eventConfigs)))
+		}
+
+		if prefix = e.next(); prefix == nil {
+			return
+		}
+	}
+}
+
+// choice is a decision taken by an explorer.
+type choice struct {
+	index        int
+	alternatives int
+	time         time.Time
+}
+
+// explorer is an events.Chooser that replays the choices of prefix and
+// takes the first alternative after, recording all choices and the
+// Event s popped.
+type explorer struct {
+	prefix   []int
+	choices  []choice
+	popped   []events.Event
+	deviated bool
+}
+
+func (e *explorer) Choose(candidates []events.Event) int {
+	index := 0
+
+	if len(candidates) > 1 {
+		if len(e.choices) < len(e.prefix) {
+			index = e.prefix[len(e.choices)]
+		}
+
+		if index >= len(candidates) {
+			e.deviated = true
+			index = 0
+		}
+
+		e.choices = append(e.choices, choice{index: index, alternatives: len(candidates), time: candidates[0].Time})
+	}
+
+	e.popped = append(e.popped, candidates[index])
+
+	return index
+}
+
+// next returns the prefix of choices leading to the next ordering to
+// explore, or nil if all have been explored.
+func (e *explorer) next() []int {
+	for i := len(e.choices) - 1; i >= 0; i-- {
+		if e.choices[i].index+1 >= e.choices[i].alternatives {
+			continue
+		}
+
+		prefix := make([]int, i+1)
+		for j := range e.choices[:i] {
+			prefix[j] = e.choices[j].index
+		}
+		prefix[i] = e.choices[i].index + 1
+
+		return prefix
+	}
+
+	return nil
+}
+
+// configs returns config.Config s that pin the order of the Event s
+// popped at the times a choice has been taken.
+func (e *explorer) configs(eventConfigs *events.Configs) []config.Config {
+	var result []config.Config
+
+	for _, event := range e.popped {
+		if !slices.ContainsFunc(e.choices, func(c choice) bool { return c.time.Equal(event.Time) }) {
+			continue
+		}
+
+		priority := 0
+		pinned := false
+		for _, configuration := range result {
+			if !configuration.Time.Equal(event.Time) {
+				continue
+			}
+
+			if slices.Equal(configuration.Tags, event.Tags()) {
+				pinned = true
+				break
+			}
+			priority++
+		}
+
+		if !pinned {
+			result = append(result, eventConfigs.Pinned(&event, priority))
+		}
+	}
+
+	return result
+}
+
+// formatConfigs formats the config.Config s returned by explorer.configs
+// like Go source code.
+func formatConfigs(configs []config.Config) string {
+	var b strings.Builder
+
+	for _, configuration := range configs {
+		fmt.Fprintf(&b, "\tconfig.Config{Tags: %#v, Time: time.UnixMilli(%d), Priority: %d", configuration.Tags, configuration.Time.UnixMilli(), configuration.Priority)
+
+		if len(configuration.WaitFor) > 0 {
+			fmt.Fprintf(&b, ", WaitFor: %#v", configuration.WaitFor)
+		}
+
+		if len(configuration.Adds) > 0 {
+			b.WriteString(", Adds: []*config.Generator{")
+			for i, generator := range configuration.Adds {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				fmt.Fprintf(&b, "{Tags: %#v, Count: %d}", generator.Tags, generator.Count)
+			}
+			b.WriteString("}")
+		}
+
+		if configuration.Duration != nil {
+			fmt.Fprintf(&b, ", Duration: %#v", configuration.Duration)
+		}
+
+		b.WriteString("},\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package simulation
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name    string
+		tags    []string
+		configs []config.Config
+		want    []string
+	}{
+		{
+			name: "all orderings",
+			tags: []string{"a", "b", "c"},
+			want: []string{"abc", "acb", "bac", "bca", "cab", "cba"},
+		},
+		{
+			name: "same tags",
+			tags: []string{"a", "b", "a"},
+			want: []string{"aab", "aba", "baa"},
+		},
+		{
+			name: "wait for",
+			tags: []string{"a", "b", "c"},
+			configs: []config.Config{
+				{Tags: []string{"b"}, WaitFor: []config.Event{config.All{Tags: []string{"a"}}}},
+			},
+			want: []string{"abc", "acb", "cab"},
+		},
+		{
+			name: "priority",
+			tags: []string{"a", "b", "c"},
+			configs: []config.Config{
+				{Tags: []string{"c"}, Priority: -1},
+			},
+			want: []string{"cab", "cba"},
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var orders []string
+			Explore(t, now, func(t *testing.T, s *Scheduler) {
+				s.ConfigureEvents(tt.configs...)
+				orders = append(orders, strings.Join(performTagged(s, tt.tags), ""))
+			})
+
+			require.ElementsMatch(t, tt.want, orders)
+		})
+	}
+}
+
+func TestExplorer_configs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	userConfigs := []config.Config{
+		{Tags: []string{"b"}, WaitFor: []config.Event{config.All{Tags: []string{"a"}}}},
+	}
+
+	s := NewScheduler(now, RunUntilIdle())
+	s.ConfigureEvents(userConfigs...)
+	e := &explorer{prefix: []int{1}}
+	s.eventQueue.SetChooser(e)
+
+	order := performTagged(s, []string{"a", "b", "c"})
+	require.Equal(t, []string{"c", "a", "b"}, order)
+
+	reproduced := NewScheduler(now, RunUntilIdle())
+	reproduced.ConfigureEvents(userConfigs...)
+	reproduced.ConfigureEvents(e.configs(s.eventConfigs)...)
+
+	require.Equal(t, order, performTagged(reproduced, []string{"a", "b", "c"}))
+	require.Equal(t,
+		"\tconfig.Config{Tags: []string{\"c\"}, Time: time.UnixMilli(1704110400000), Priority: 0},\n"+
+			"\tconfig.Config{Tags: []string{\"a\"}, Time: time.UnixMilli(1704110400000), Priority: 1},\n"+
+			"\tconfig.Config{Tags: []string{\"b\"}, Time: time.UnixMilli(1704110400000), Priority: 2, WaitFor: []config.Event{config.All{Tags:[]string{\"a\"}, Selector:\"\"}}},\n",
+		formatConfigs(e.configs(s.eventConfigs)),
+	)
+}
+
+// performTagged performs an action for each of tags at the current time
+// of s, and returns the tags in the order they have been performed in.
+func performTagged(s *Scheduler, tags []string) []string {
+	var order []string
+	var mu sync.Mutex
+	for _, tag := range tags {
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, tag)
+		}), tag)
+	}
+
+	s.Forward(time.Minute)
+
+	return order
+}
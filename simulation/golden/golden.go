@@ -0,0 +1,48 @@
+// Package golden compares the Trace of a simulation.Scheduler with a
+// golden file, in order to detect changes in the order of Event s that
+// assertions on the final state of a test miss.
+//
+// Golden files are written by running the test with -timestone.update
+// rather than the customary -update: the package registers its flag when
+// it is initialized, before the test package importing it, which would
+// panic when registering an -update flag of its own.
+package golden
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/metamogul/timestone/v2/simulation"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("timestone.update", false, "update the golden files of simulation traces")
+
+// AssertTraceMatches fails t unless the textual representation of the
+// Trace recorded by s, as returned by simulation.Trace.String, matches
+// the golden file at path. If the test is run with the
+// -timestone.update flag, the golden file is written instead. s must
+// have been created with simulation.RecordTrace.
+func AssertTraceMatches(t testing.TB, s *simulation.Scheduler, path string) {
+	t.Helper()
+
+	trace := s.Trace()
+	if trace == nil {
+		t.Fatal("The Scheduler must be created with simulation.RecordTrace to assert its trace")
+		return
+	}
+
+	got := trace.String()
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "Run the test with -timestone.update to create the golden file")
+	require.Equal(t, string(want), got, "The trace doesn't match %s, run the test with -timestone.update if the change is intended", path)
+}
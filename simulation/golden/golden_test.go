@@ -0,0 +1,96 @@
+package golden
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingT records whether AssertTraceMatches has failed.
+type recordingT struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingT) Helper()               {}
+func (r *recordingT) Errorf(string, ...any) { r.failed = true }
+func (r *recordingT) Fatal(...any)          { r.failed = true }
+func (r *recordingT) FailNow()              { r.failed = true }
+
+// newScheduler returns a Scheduler that has performed actions tagged
+// with tags in this order.
+func newScheduler(options []simulation.Option, tags ...string) *simulation.Scheduler {
+	s := simulation.NewScheduler(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), options...)
+	s.ConfigureEvents(config.Config{Tags: []string{"first"}, Duration: config.Fixed(time.Minute)})
+
+	for i, tag := range tags {
+		s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) {}), time.Duration(i)*time.Second, tag)
+	}
+	s.Forward(time.Hour)
+
+	return s
+}
+
+func TestAssertTraceMatches(t *testing.T) {
+	t.Parallel()
+
+	AssertTraceMatches(t, newScheduler([]simulation.Option{simulation.RecordTrace(), simulation.RunUntilIdle()}, "first", "second"), "testdata/trace.golden")
+}
+
+func TestAssertTraceMatches_mismatch(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name    string
+		options []simulation.Option
+		path    string
+	}{
+		{
+			name:    "different order",
+			options: []simulation.Option{simulation.RecordTrace(), simulation.RunUntilIdle()},
+			path:    "testdata/trace.golden",
+		},
+		{
+			name:    "missing golden file",
+			options: []simulation.Option{simulation.RecordTrace(), simulation.RunUntilIdle()},
+			path:    "testdata/missing.golden",
+		},
+		{
+			name: "no trace recorded",
+			path: "testdata/trace.golden",
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &recordingT{TB: t}
+			AssertTraceMatches(recorder, newScheduler(tt.options, "second", "first"), tt.path)
+
+			require.True(t, recorder.failed)
+		})
+	}
+}
+
+func TestAssertTraceMatches_update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "trace.golden")
+	s := newScheduler([]simulation.Option{simulation.RecordTrace(), simulation.RunUntilIdle()}, "first", "second")
+
+	*update = true
+	AssertTraceMatches(t, s, path)
+	*update = false
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, s.Trace().String(), string(written))
+
+	AssertTraceMatches(t, s, path)
+}
@@ -0,0 +1,132 @@
+package simulation
+
+import (
+	"context"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/internal"
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+)
+
+// handle implements timestone.Handle for the Event s materialized by an
+// events.ResettableGenerator. It is added to the event queue in place of
+// the generator it decorates, in order to keep track of the executions.
+type handle struct {
+	*internal.Lifecycle
+
+	generator events.ResettableGenerator
+	scheduler *Scheduler
+	tags      []string
+	observers *internal.Observers
+}
+
+func newHandle(
+	ctx context.Context,
+	scheduler *Scheduler,
+	generator events.ResettableGenerator,
+	tags []string,
+	observers *internal.Observers,
+) *handle {
+	return &handle{
+		Lifecycle: internal.NewObservedLifecycle(ctx, func() {
+			observers.Cancelled(tags, scheduler.clock.Now())
+		}),
+		generator: generator,
+		scheduler: scheduler,
+		tags:      tags,
+		observers: observers,
+	}
+}
+
+func (h *handle) Pop() *events.Event {
+	event := h.generator.Pop()
+
+	started := h.Start()
+	if h.generator.Finished() {
+		h.Exhaust()
+	} else if started {
+		h.observers.Scheduled(h.tags, h.generator.Peek().Time)
+	}
+
+	action := event.Action
+	event.Action = timestone.SimpleAction(func(ctx context.Context) {
+		if !started {
+			return
+		}
+		defer h.Finish()
+
+		clock, ok := ctx.Value(timestone.ActionContextClockKey).(timestone.Clock)
+		if !ok {
+			clock = h.scheduler.clock
+		}
+
+		h.perform(ctx, action, clock)
+
+		// Keep the handle running for as long as it takes to perform the
+		// action according to its configuration.
+		if execution, ok := clock.(*execution); ok {
+			execution.elapse()
+		}
+
+		h.observers.Finished(h.tags, clock.Now())
+	})
+
+	return event
+}
+
+// perform performs action, notifying the observers when it starts or panics.
+func (h *handle) perform(ctx context.Context, action timestone.Action, clock timestone.Clock) {
+	defer h.observers.Recover(h.tags, clock)
+
+	h.observers.Started(h.tags, clock.Now())
+	action.Perform(ctx)
+}
+
+func (h *handle) Peek() events.Event {
+	return h.generator.Peek()
+}
+
+// Preview implements events.Previewer.
+func (h *handle) Preview(until time.Time) []time.Time {
+	if h.Finished() {
+		return nil
+	}
+
+	return events.Preview(h.generator, until)
+}
+
+func (h *handle) Finished() bool {
+	return h.generator.Finished() || h.Cancelled()
+}
+
+// Cancel implements timestone.Handle and removes the generator from the
+// event queue right away.
+func (h *handle) Cancel() {
+	h.Lifecycle.Cancel()
+
+	h.scheduler.eventGeneratorsMu.Lock()
+	defer h.scheduler.eventGeneratorsMu.Unlock()
+
+	h.scheduler.eventQueue.Update(h)
+}
+
+// Reset implements timestone.Handle, measuring duration from the current
+// time of the Scheduler's clock.
+func (h *handle) Reset(duration time.Duration) bool {
+	h.scheduler.eventGeneratorsMu.Lock()
+	defer h.scheduler.eventGeneratorsMu.Unlock()
+
+	if !h.Revive() {
+		return false
+	}
+
+	h.generator.Reset(h.scheduler.clock.Now(), duration)
+	h.scheduler.eventQueue.Update(h)
+
+	if !h.generator.Finished() {
+		h.observers.Scheduled(h.tags, h.generator.Peek().Time)
+	}
+
+	return true
+}
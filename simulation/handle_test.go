@@ -0,0 +1,211 @@
+package simulation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/schedule"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+type executionRecorder struct {
+	executionTimes []time.Time
+	mu             sync.Mutex
+}
+
+func (e *executionRecorder) action() timestone.Action {
+	return timestone.SimpleAction(func(ctx context.Context) {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		e.executionTimes = append(e.executionTimes, ctx.Value(timestone.ActionContextClockKey).(timestone.Clock).Now())
+	})
+}
+
+func (e *executionRecorder) times() []time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.executionTimes
+}
+
+func TestHandle_Cancel(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	handle := s.PerformRepeatedlyWithHandle(context.Background(), recorder.action(), nil, time.Minute, "job")
+
+	s.Forward(2 * time.Minute)
+	require.Equal(t, timestone.StatusScheduled, handle.Status())
+
+	handle.Cancel()
+
+	require.True(t, s.eventQueue.Finished())
+	require.Equal(t, timestone.StatusCancelled, handle.Status())
+	require.False(t, handle.Reset(time.Minute))
+	<-handle.Done()
+
+	s.Forward(2 * time.Minute)
+	require.ElementsMatch(t, []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)}, recorder.times())
+	require.Equal(t, 2, handle.Runs())
+}
+
+func TestHandle_Cancel_context(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := NewScheduler(now)
+	handle := s.PerformAfterWithHandle(ctx, timestone.NewMockAction(t), time.Minute, "job")
+	otherHandle := s.PerformAfterWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {}), 2*time.Minute, "otherJob")
+
+	cancel()
+	<-handle.Done()
+	require.Equal(t, timestone.StatusCancelled, handle.Status())
+
+	s.Forward(time.Hour)
+	require.Equal(t, timestone.StatusFinished, otherHandle.Status())
+}
+
+func TestHandle_Reset(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+
+	// A debounced action is only performed once things calm down.
+	handle := s.PerformAfterWithHandle(context.Background(), recorder.action(), time.Minute, "debounced")
+
+	s.Forward(30 * time.Second)
+	require.True(t, handle.Reset(time.Minute))
+
+	s.Forward(45 * time.Second)
+	require.True(t, handle.Reset(time.Minute))
+	require.Empty(t, recorder.times())
+
+	s.Forward(time.Hour)
+	require.Equal(t, []time.Time{now.Add(135 * time.Second)}, recorder.times())
+	require.Equal(t, timestone.StatusFinished, handle.Status())
+	<-handle.Done()
+
+	require.False(t, handle.Reset(time.Minute))
+}
+
+func TestHandle_Reset_fromAction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	s.ConfigureEvents(config.Config{
+		Tags: []string{"rearming"},
+		Adds: []*config.Generator{{Tags: []string{"rearming"}, Count: 1}},
+	})
+
+	var handle timestone.Handle
+	handle = s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		recorder.action().Perform(ctx)
+
+		// Resetting counts as adding a generator, so the last execution
+		// resets as well in order to satisfy the configuration.
+		if handle.Runs() < 3 {
+			handle.Reset(time.Minute)
+		} else {
+			handle.Reset(time.Hour)
+			handle.Cancel()
+		}
+	}), "rearming")
+
+	s.Forward(time.Hour)
+
+	require.Equal(t, []time.Time{now, now.Add(time.Minute), now.Add(2 * time.Minute)}, recorder.times())
+	<-handle.Done()
+}
+
+func TestHandle_Reset_periodic(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	handle := s.PerformRepeatedlyWithHandle(context.Background(), recorder.action(), nil, time.Minute, "job")
+
+	s.Forward(90 * time.Second)
+	handle.Reset(10 * time.Second)
+	s.Forward(30 * time.Second)
+
+	require.ElementsMatch(t, []time.Time{
+		now.Add(60 * time.Second),
+		now.Add(100 * time.Second),
+		now.Add(110 * time.Second),
+		now.Add(120 * time.Second),
+	}, recorder.times())
+}
+
+func TestHandle_Reset_scheduled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	handle := s.PerformScheduledWithHandle(context.Background(), recorder.action(), schedule.Every(now, time.Hour), "job")
+
+	handle.Reset(10 * time.Minute)
+	s.Forward(2 * time.Hour)
+
+	require.ElementsMatch(t, []time.Time{
+		now.Add(10 * time.Minute),
+		now.Add(time.Hour),
+		now.Add(2 * time.Hour),
+	}, recorder.times())
+}
+
+func TestHandle_Status(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handle := s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {
+		close(started)
+		<-release
+	}), "job")
+	require.Equal(t, timestone.StatusScheduled, handle.Status())
+
+	s.ForwardOne()
+	<-started
+	require.Equal(t, timestone.StatusRunning, handle.Status())
+
+	close(release)
+	s.Wait()
+	require.Equal(t, timestone.StatusFinished, handle.Status())
+	require.Equal(t, 1, handle.Runs())
+	<-handle.Done()
+}
+
+func TestHandle_nothingScheduled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	handle := s.PerformScheduledWithHandle(context.Background(), timestone.NewMockAction(t), schedule.At(), "job")
+
+	<-handle.Done()
+	require.Equal(t, timestone.StatusFinished, handle.Status())
+}
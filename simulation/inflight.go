@@ -0,0 +1,62 @@
+package simulation
+
+import "sync"
+
+// inflight counts the actions of a Scheduler created with RunUntilIdle
+// that are in flight, that is neither finished nor parked, so that the
+// run loop can wait until none of them can add event generators anymore.
+// An action adds generators through the context.Context passed to it,
+// which carries its execution, and completes by finishing or parking
+// that execution, so no action goes unnoticed as long as it doesn't
+// leave either to a goroutine outliving it.
+type inflight struct {
+	count int
+	// idle is closed once count has dropped to zero.
+	idle chan struct{}
+	mu   sync.Mutex
+}
+
+// started is called by the run loop for every Event it executes. i is
+// nil unless the Scheduler has been created with RunUntilIdle.
+func (i *inflight) started() {
+	if i == nil {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.count == 0 {
+		i.idle = make(chan struct{})
+	}
+	i.count++
+}
+
+// stopped is called once the action of an Event has finished or has been
+// parked.
+func (i *inflight) stopped() {
+	if i == nil {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.count--
+	if i.count == 0 {
+		close(i.idle)
+	}
+}
+
+// wait blocks until no action is in flight, or stop is closed. As only
+// the run loop starts Event s, no action starts in the meantime.
+func (i *inflight) wait(stop <-chan struct{}) {
+	i.mu.Lock()
+	idle := i.idle
+	count := i.count
+	i.mu.Unlock()
+
+	if count > 0 {
+		closed(idle)(stop)
+	}
+}
@@ -0,0 +1,99 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+	"github.com/metamogul/timestone/v2/simulation/internal/selector"
+)
+
+// EventInfo describes an Event in the event queue of a Scheduler.
+type EventInfo struct {
+	Tags []string
+	Time time.Time
+	// Resumption is true if the Event resumes a parked action.
+	Resumption bool
+}
+
+func eventInfo(event *events.Event) EventInfo {
+	return EventInfo{Tags: event.Tags(), Time: event.Time, Resumption: event.Resumption()}
+}
+
+// GeneratorInfo describes an event generator in the event queue of a
+// Scheduler, by the tags and time of the next Event it materializes.
+type GeneratorInfo struct {
+	Tags []string
+	Next time.Time
+}
+
+// NextEventTime returns the time of the next Event in the event queue,
+// or false if the event queue is empty.
+func (s *Scheduler) NextEventTime() (time.Time, bool) {
+	s.eventGeneratorsMu.Lock()
+	defer s.eventGeneratorsMu.Unlock()
+
+	if s.eventQueue.Finished() {
+		return time.Time{}, false
+	}
+
+	return s.eventQueue.Peek().Time, true
+}
+
+// Pending returns the Event s the event queue holds for the time between
+// the current time of the Scheduler's clock and within after it, in the
+// order of their time. Of generators added via AddEventGenerators, only
+// the next Event is known.
+func (s *Scheduler) Pending(within time.Duration) []EventInfo {
+	s.eventGeneratorsMu.Lock()
+	upcoming := s.eventQueue.Upcoming(s.clock.Now().Add(within))
+	s.eventGeneratorsMu.Unlock()
+
+	result := make([]EventInfo, len(upcoming))
+	for i, event := range upcoming {
+		result[i] = eventInfo(&event)
+	}
+
+	return result
+}
+
+// PendingCount returns the number of event generators in the event queue
+// whose next Event is selected by sel, regardless of its time. An empty
+// sel selects all. After timestone.Handle.Cancel, e.g., the generator of
+// the handle no longer counts. It returns an error if sel can't be
+// parsed.
+func (s *Scheduler) PendingCount(sel config.Selector) (int, error) {
+	var expression selector.Expression
+	if sel != "" {
+		var err error
+		if expression, err = selector.Parse(string(sel)); err != nil {
+			return 0, err
+		}
+	}
+
+	result := 0
+	for _, generator := range s.Generators() {
+		if expression == nil || expression.Matches(selector.Set(generator.Tags)) {
+			result++
+		}
+	}
+
+	return result, nil
+}
+
+// Generators returns the event generators in the event queue that
+// haven't finished, in the order their next Event s will be executed.
+func (s *Scheduler) Generators() []GeneratorInfo {
+	s.eventGeneratorsMu.Lock()
+	defer s.eventGeneratorsMu.Unlock()
+
+	generators := s.eventQueue.Generators()
+
+	result := make([]GeneratorInfo, len(generators))
+	for i, generator := range generators {
+		next := generator.Peek()
+		result[i] = GeneratorInfo{Tags: next.Tags(), Next: next.Time}
+	}
+
+	return result
+}
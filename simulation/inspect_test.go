@@ -0,0 +1,78 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_NextEventTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	_, ok := s.NextEventTime()
+	require.False(t, ok)
+
+	s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) {}), time.Hour, "job")
+
+	next, ok := s.NextEventTime()
+	require.True(t, ok)
+	require.Equal(t, now.Add(time.Hour), next)
+}
+
+func TestScheduler_Pending(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	noop := timestone.SimpleAction(func(context.Context) {})
+
+	s := NewScheduler(now)
+	poll := s.PerformRepeatedlyWithHandle(context.Background(), noop, nil, 10*time.Minute, "poll", "tenant=a")
+	s.PerformAfter(context.Background(), noop, 15*time.Minute, "report")
+	err := s.PerformOnSchedule(context.Background(), noop, "0 * * * *", "hourly")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]EventInfo{
+			{Tags: []string{"poll", "tenant=a"}, Time: now.Add(10 * time.Minute)},
+			{Tags: []string{"report"}, Time: now.Add(15 * time.Minute)},
+			{Tags: []string{"poll", "tenant=a"}, Time: now.Add(20 * time.Minute)},
+		},
+		s.Pending(20*time.Minute),
+	)
+	require.Len(t, s.Pending(time.Hour), 8)
+
+	require.Equal(t,
+		[]GeneratorInfo{
+			{Tags: []string{"poll", "tenant=a"}, Next: now.Add(10 * time.Minute)},
+			{Tags: []string{"report"}, Next: now.Add(15 * time.Minute)},
+			{Tags: []string{"hourly"}, Next: now.Add(time.Hour)},
+		},
+		s.Generators(),
+	)
+
+	pendingCount := func(sel config.Selector) int {
+		count, err := s.PendingCount(sel)
+		require.NoError(t, err)
+		return count
+	}
+
+	require.Equal(t, 3, pendingCount(""))
+	require.Equal(t, 1, pendingCount("poll && tenant=a"))
+
+	_, err = s.PendingCount("poll &&")
+	require.Error(t, err)
+
+	s.Forward(30 * time.Minute)
+	require.Equal(t, 2, pendingCount(""))
+
+	poll.Cancel()
+	require.Zero(t, pendingCount("poll"))
+	require.Empty(t, s.Pending(20*time.Minute))
+}
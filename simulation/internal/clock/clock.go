@@ -1,11 +1,16 @@
 package clock
 
 import (
+	"sync"
 	"time"
 )
 
+// Clock is the clock of a simulation.Scheduler. It is safe for concurrent
+// use, as it is read from actions and context callbacks while the run
+// loop sets it.
 type Clock struct {
 	now time.Time
+	mu  sync.RWMutex
 }
 
 func NewClock(now time.Time) *Clock {
@@ -15,10 +20,16 @@ func NewClock(now time.Time) *Clock {
 }
 
 func (c *Clock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	return c.now
 }
 
 func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if t.Before(c.now) {
 		panic("time can't be in the past")
 	}
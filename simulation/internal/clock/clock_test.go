@@ -23,7 +23,7 @@ func TestClock_Now(t *testing.T) {
 
 	now := time.Now()
 
-	clock := Clock{now}
+	clock := Clock{now: now}
 	require.Equal(t, now, clock.Now())
 }
 
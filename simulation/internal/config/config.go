@@ -2,21 +2,66 @@ package config
 
 import (
 	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/metamogul/timestone/v2/simulation/internal/selector"
 	"time"
 )
 
 type At struct {
-	Tags []string
-	Time time.Time
+	Tags     []string
+	Selector config.Selector
+	Time     time.Time
 }
 
 func (b At) GetTags() []string {
 	return b.Tags
 }
 
+func (b At) GetSelector() config.Selector {
+	return b.Selector
+}
+
 func Convert(before config.Before, eventTime time.Time) At {
 	return At{
-		Tags: before.Tags,
-		Time: eventTime.Add(before.Interval),
+		Tags:     before.Tags,
+		Selector: before.Selector,
+		Time:     eventTime.Add(before.Interval),
+	}
+}
+
+// Target is a config.Event waited for or explained, along with its
+// Selector as parsed once, so that matching events can't fail.
+type Target struct {
+	config.Event
+	// Expression is nil if the config.Event has no Selector.
+	Expression selector.Expression
+}
+
+// ParseTarget returns event as a Target, or an error if its Selector
+// can't be parsed.
+func ParseTarget(event config.Event) (Target, error) {
+	result := Target{Event: event}
+
+	if event.GetSelector() != "" {
+		expression, err := selector.Parse(string(event.GetSelector()))
+		if err != nil {
+			return Target{}, err
+		}
+		result.Expression = expression
 	}
+
+	return result, nil
+}
+
+// Events returns the config.Event s of targets.
+func Events(targets []Target) []config.Event {
+	if targets == nil {
+		return nil
+	}
+
+	result := make([]config.Event, len(targets))
+	for i, target := range targets {
+		result[i] = target.Event
+	}
+
+	return result
 }
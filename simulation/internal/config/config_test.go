@@ -13,9 +13,11 @@ func TestConvert(t *testing.T) {
 	before := config.Before{
 		Interval: -1,
 		Tags:     []string{"test"},
+		Selector: "shard=1",
 	}
 
 	beforeInternal := Convert(before, time.Time{})
 	require.Equal(t, before.Tags, beforeInternal.Tags)
+	require.Equal(t, before.Selector, beforeInternal.Selector)
 	require.Equal(t, time.Time{}.Add(before.Interval), beforeInternal.Time)
 }
@@ -1,5 +1,11 @@
 package data
 
+import (
+	"strings"
+
+	"github.com/metamogul/timestone/v2/simulation/internal/selector"
+)
+
 // To support very large sets, there's great potential
 // for optimization here using a prefix tree as an index,
 // as well as compressing sparse bitmaps. For small sets
@@ -71,6 +77,27 @@ func (t *TaggedStore[T]) ContainedIn(tags []string) []T {
 	return result
 }
 
+// Selecting returns the values whose tags contain tags and are selected
+// by expression, if it isn't nil.
+func (t *TaggedStore[T]) Selecting(tags []string, expression selector.Expression) []T {
+	bitmaskForTags := t.bitmapForTags(tags)
+
+	result := make([]T, 0, len(t.content))
+	for _, entry := range t.content {
+		if !entry.contains(bitmaskForTags) {
+			continue
+		}
+
+		if expression != nil && !expression.Matches(entryTags[T]{store: t, bitmap: entry.bitmap}) {
+			continue
+		}
+
+		result = append(result, entry.value)
+	}
+
+	return result
+}
+
 func (t *TaggedStore[T]) Matching(tags []string) T {
 	bitmaskForTags := t.bitmapForTags(tags)
 
@@ -111,3 +138,24 @@ func (t *TaggedStore[T]) bitmapForTags(tags []string) bitmap {
 
 	return tagsBitmask
 }
+
+// entryTags implements selector.Tags for the tags of an entry of store.
+type entryTags[T any] struct {
+	store  *TaggedStore[T]
+	bitmap bitmap
+}
+
+func (e entryTags[T]) Has(tag string) bool {
+	bitmapForTag, ok := e.store.bitmapsByTags[tag]
+	return ok && e.bitmap.contains(bitmapForTag)
+}
+
+func (e entryTags[T]) HasPrefix(prefix string) bool {
+	for tag, bitmapForTag := range e.store.bitmapsByTags {
+		if strings.HasPrefix(tag, prefix) && e.bitmap.contains(bitmapForTag) {
+			return true
+		}
+	}
+
+	return false
+}
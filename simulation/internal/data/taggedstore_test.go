@@ -1,6 +1,7 @@
 package data
 
 import (
+	"github.com/metamogul/timestone/v2/simulation/internal/selector"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -180,6 +181,64 @@ func Test_TaggedStore_ContainedIn(t *testing.T) {
 	}
 }
 
+func Test_TaggedStore_Selecting(t *testing.T) {
+	t.Parallel()
+
+	value1 := "value"
+	tags1 := []string{"job:import", "tenant=a"}
+
+	value2 := "apple"
+	tags2 := []string{"job:export", "tenant=b"}
+
+	tests := []struct {
+		name       string
+		getForTags []string
+		selector   string
+		want       []string
+	}{
+		{
+			name:       "no selector",
+			getForTags: []string{"job:import"},
+			want:       []string{value1},
+		},
+		{
+			name:     "select prefix",
+			selector: "job:*",
+			want:     []string{value1, value2},
+		},
+		{
+			name:     "select negation",
+			selector: "job:* && tenant!=a",
+			want:     []string{value2},
+		},
+		{
+			name:       "select within tags",
+			getForTags: []string{"tenant=a"},
+			selector:   "job:export || tenant=b",
+			want:       []string{},
+		},
+		{
+			name:     "select unknown tag",
+			selector: "job:sync",
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := NewTaggedStore[string]()
+
+			ts.Set(value1, tags1)
+			ts.Set(value2, tags2)
+
+			value := ts.Selecting(tt.getForTags, selector.MustParse(tt.selector))
+			require.Equal(t, tt.want, value)
+		})
+	}
+}
+
 func Test_TaggedStore_Matching(t *testing.T) {
 	t.Parallel()
 
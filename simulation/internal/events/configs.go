@@ -1,9 +1,13 @@
 package events
 
 import (
+	"fmt"
 	"github.com/metamogul/timestone/v2/simulation/config"
 	configinternal "github.com/metamogul/timestone/v2/simulation/internal/config"
 	"github.com/metamogul/timestone/v2/simulation/internal/data"
+	"github.com/metamogul/timestone/v2/simulation/internal/selector"
+	"maps"
+	"sync"
 	"time"
 )
 
@@ -13,21 +17,88 @@ const (
 	EventPriorityDefault = iota
 )
 
+// Configs holds the config.Config s for events. An event is configured by
+// the most specific config.Config whose tags it has all been tagged with
+// and whose selector it matches: a config.Config with a set Time takes
+// precedence over one without, then the one with more tags, counting
+// those its selector refers to, wins. Remaining ties are resolved in
+// favor of the config.Config that has been set last, and reported if
+// Configs is strict.
 type Configs struct {
 	configsByTags        *data.TaggedStore[*config.Config]
 	configsByTagsAndTime map[int64]*data.TaggedStore[*config.Config]
+
+	// Configs with a selector can't be told apart by their tags, so they
+	// are kept separately.
+	selectorConfigs       []*config.Config
+	selectorConfigsByTime map[int64][]*config.Config
+	// expressionsBySelectors holds the selectors of the configs and of
+	// their WaitFor as parsed by Set, so that they are never parsed again.
+	expressionsBySelectors map[config.Selector]selector.Expression
+	// invalid records the problems of the configs whose selectors can't
+	// be parsed, which Set doesn't set.
+	invalid []config.Problem
+
+	// sequence records the order in which the configs have been set.
+	sequence map[*config.Config]int
+	strict   bool
+
+	// matched records the configs that have configured an event.
+	matched map[*config.Config]bool
+	// missed records the problems of targets of WaitFor that no event
+	// existed for when they were waited for, once per config and target.
+	missed        []config.Problem
+	missedTargets map[missedTarget]bool
+	// ambiguous records the problems of ties between configs if strict,
+	// once per pair of configs.
+	ambiguous        []config.Problem
+	ambiguousConfigs map[[2]*config.Config]bool
+	matchedMu        sync.Mutex
+}
+
+// missedTarget identifies a target of the WaitFor of a config.Config.
+type missedTarget struct {
+	configuration *config.Config
+	target        string
 }
 
 func NewConfigs() *Configs {
 	return &Configs{
-		configsByTags:        data.NewTaggedStore[*config.Config](),
-		configsByTagsAndTime: make(map[int64]*data.TaggedStore[*config.Config]),
+		configsByTags:          data.NewTaggedStore[*config.Config](),
+		configsByTagsAndTime:   make(map[int64]*data.TaggedStore[*config.Config]),
+		selectorConfigsByTime:  make(map[int64][]*config.Config),
+		expressionsBySelectors: make(map[config.Selector]selector.Expression),
+		sequence:               make(map[*config.Config]int),
+		matched:                make(map[*config.Config]bool),
+		missedTargets:          make(map[missedTarget]bool),
+		ambiguousConfigs:       make(map[[2]*config.Config]bool),
 	}
 }
 
+// SetStrict makes Configs record a problem if an event matches multiple
+// configs that are equally specific, as returned by Ambiguous, besides
+// resolving the tie.
+func (c *Configs) SetStrict(strict bool) {
+	c.strict = strict
+}
+
+// Set sets config, unless its Selector or one of its WaitFor can't be
+// parsed. The problem is reported by Validate instead.
 func (c *Configs) Set(config config.Config) {
+	if problem, ok := c.parseSelectors(&config); !ok {
+		c.invalid = append(c.invalid, problem)
+		return
+	}
+
+	c.sequence[&config] = len(c.sequence)
+
+	if config.Selector != "" {
+		c.setWithSelector(&config)
+		return
+	}
+
 	if !config.Time.IsZero() {
-		c.configsByTagsForTime(config.Time).Set(&config, config.Tags)
+		c.setForTime(&config)
 		return
 	}
 
@@ -42,18 +113,20 @@ func (c *Configs) Priority(event *Event) int {
 	return EventPriorityDefault
 }
 
-func (c *Configs) BlockingEvents(event *Event) []config.Event {
+func (c *Configs) BlockingEvents(event *Event) []configinternal.Target {
 	if configuration := c.get(event); configuration != nil {
 
 		blockingEvents := configuration.WaitFor
 
-		result := make([]config.Event, len(blockingEvents))
+		result := make([]configinternal.Target, len(blockingEvents))
 		for i, blockingEvent := range blockingEvents {
+			result[i].Expression = c.expressionsBySelectors[blockingEvent.GetSelector()]
+
 			switch blockingEvent := blockingEvent.(type) {
 			case config.Before:
-				result[i] = configinternal.Convert(blockingEvent, event.Time)
+				result[i].Event = configinternal.Convert(blockingEvent, event.Time)
 			default:
-				result[i] = blockingEvent
+				result[i].Event = blockingEvent
 			}
 		}
 
@@ -64,6 +137,10 @@ func (c *Configs) BlockingEvents(event *Event) []config.Event {
 }
 
 func (c *Configs) ExpectedGenerators(event *Event) []*config.Generator {
+	if event.resumption {
+		return nil
+	}
+
 	if configuration := c.get(event); configuration != nil {
 		return configuration.Adds
 	}
@@ -71,25 +148,225 @@ func (c *Configs) ExpectedGenerators(event *Event) []*config.Generator {
 	return nil
 }
 
-func (c *Configs) configsByTagsForTime(time time.Time) *data.TaggedStore[*config.Config] {
-	result, exists := c.configsByTagsAndTime[time.UnixMilli()]
+// Duration samples how long it takes to perform the action of event.
+func (c *Configs) Duration(event *Event) time.Duration {
+	if event.resumption {
+		return 0
+	}
 
-	if !exists {
-		result = data.NewTaggedStore[*config.Config]()
-		c.configsByTagsAndTime[time.UnixMilli()] = result
+	if configuration := c.get(event); configuration != nil && configuration.Duration != nil {
+		return configuration.Duration.Sample()
+	}
+
+	return 0
+}
+
+// Pinned returns a config.Config that configures event like it is
+// configured now, but with priority and only for events with the same
+// tags at the same time.
+func (c *Configs) Pinned(event *Event, priority int) config.Config {
+	var result config.Config
+	if configuration := c.get(event); configuration != nil {
+		result = *configuration
 	}
 
+	result.Tags = event.tags
+	result.Selector = ""
+	result.Time = event.Time
+	result.Priority = priority
+
 	return result
 }
 
+// parseSelectors parses the Selector of configuration and those of its
+// WaitFor, unless they have been parsed already. It returns a problem
+// if one of them can't be parsed.
+func (c *Configs) parseSelectors(configuration *config.Config) (config.Problem, bool) {
+	selectors := []config.Selector{configuration.Selector}
+	for _, target := range configuration.WaitFor {
+		selectors = append(selectors, target.GetSelector())
+	}
+
+	expressions := make(map[config.Selector]selector.Expression)
+	for _, sel := range selectors {
+		if _, parsed := c.expressionsBySelectors[sel]; parsed || sel == "" {
+			continue
+		}
+
+		expression, err := selector.Parse(string(sel))
+		if err != nil {
+			return config.Problem{
+				Kind:        config.ProblemInvalidSelector,
+				Configs:     []config.Config{*configuration},
+				Explanation: fmt.Sprintf("%s isn't configured: %v", explain(configuration), err),
+			}, false
+		}
+		expressions[sel] = expression
+	}
+
+	maps.Copy(c.expressionsBySelectors, expressions)
+
+	return config.Problem{}, true
+}
+
+func (c *Configs) setWithSelector(configuration *config.Config) {
+	if !configuration.Time.IsZero() {
+		timeUnixMilli := configuration.Time.UnixMilli()
+		c.selectorConfigsByTime[timeUnixMilli] = replaceOrAppend(c.selectorConfigsByTime[timeUnixMilli], configuration)
+		return
+	}
+
+	c.selectorConfigs = replaceOrAppend(c.selectorConfigs, configuration)
+}
+
+// replaceOrAppend replaces the config in configs with the same tags and
+// selector as configuration, or appends configuration if there is none.
+func replaceOrAppend(configs []*config.Config, configuration *config.Config) []*config.Config {
+	for i, existing := range configs {
+		if existing.Selector == configuration.Selector && sameTags(existing.Tags, configuration.Tags) {
+			configs[i] = configuration
+			return configs
+		}
+	}
+
+	return append(configs, configuration)
+}
+
 func (c *Configs) get(event *Event) *config.Config {
-	if configuration := c.configsByTagsForTime(event.Time).Matching(event.tags); configuration != nil {
-		return configuration
+	configuration := c.lookup(event)
+
+	if configuration != nil {
+		c.matchedMu.Lock()
+		c.matched[configuration] = true
+		c.matchedMu.Unlock()
 	}
 
-	if configuration := c.configsByTags.Matching(event.tags); configuration != nil {
+	return configuration
+}
+
+// Applied returns the config.Config that applies to events with tags at
+// time, or nil if there is none. Unlike the other getters, it doesn't
+// record the config.Config as matched.
+func (c *Configs) Applied(time time.Time, tags []string) *config.Config {
+	return c.lookup(&Event{Time: time, tags: tags})
+}
+
+func (c *Configs) lookup(event *Event) *config.Config {
+	var candidates []*config.Config
+	if configsByTags := c.configsByTagsForTime(event.Time); configsByTags != nil {
+		candidates = configsByTags.ContainedIn(event.tags)
+	}
+	candidates = append(candidates, c.selecting(event, c.selectorConfigsByTime[event.Time.UnixMilli()])...)
+	if configuration := c.mostSpecific(event, candidates); configuration != nil {
 		return configuration
 	}
 
-	return nil
+	candidates = append(
+		c.configsByTags.ContainedIn(event.tags),
+		c.selecting(event, c.selectorConfigs)...,
+	)
+
+	return c.mostSpecific(event, candidates)
+}
+
+func (c *Configs) setForTime(configuration *config.Config) {
+	timeUnixMilli := configuration.Time.UnixMilli()
+
+	configsByTags, exists := c.configsByTagsAndTime[timeUnixMilli]
+	if !exists {
+		configsByTags = data.NewTaggedStore[*config.Config]()
+		c.configsByTagsAndTime[timeUnixMilli] = configsByTags
+	}
+
+	configsByTags.Set(configuration, configuration.Tags)
+}
+
+// configsByTagsForTime returns the configs without a selector set for
+// time, or nil if there are none. Unlike setForTime, it doesn't add an
+// entry for time, as lookups run concurrently.
+func (c *Configs) configsByTagsForTime(time time.Time) *data.TaggedStore[*config.Config] {
+	return c.configsByTagsAndTime[time.UnixMilli()]
+}
+
+// selecting returns the configs with a selector that apply to event.
+func (c *Configs) selecting(event *Event, configs []*config.Config) []*config.Config {
+	var result []*config.Config
+
+	for _, configuration := range configs {
+		if selector.Set(event.tags).HasAll(configuration.Tags) && c.expressionsBySelectors[configuration.Selector].Matches(selector.Set(event.tags)) {
+			result = append(result, configuration)
+		}
+	}
+
+	return result
+}
+
+// specificity returns the number of tags configuration refers to.
+func (c *Configs) specificity(configuration *config.Config) int {
+	result := len(configuration.Tags)
+
+	if configuration.Selector != "" {
+		result += c.expressionsBySelectors[configuration.Selector].Terms()
+	}
+
+	return result
+}
+
+func (c *Configs) mostSpecific(event *Event, configs []*config.Config) *config.Config {
+	var result, tied *config.Config
+
+	for _, configuration := range configs {
+		switch {
+		case result == nil || c.specificity(configuration) > c.specificity(result):
+			result, tied = configuration, nil
+		case c.specificity(configuration) == c.specificity(result):
+			if c.sequence[configuration] > c.sequence[result] {
+				result, tied = configuration, result
+			} else {
+				tied = configuration
+			}
+		}
+	}
+
+	if c.strict && tied != nil {
+		c.tied(event, tied, result)
+	}
+
+	return result
+}
+
+// describe returns a description of configuration for error messages.
+func describe(configuration *config.Config) string {
+	if configuration.Selector == "" {
+		return fmt.Sprintf("tagged %v", configuration.Tags)
+	}
+
+	return fmt.Sprintf("tagged %v selecting %q", configuration.Tags, configuration.Selector)
+}
+
+// Targets reports whether target, e.g. as returned by BlockingEvents,
+// refers to event.
+func Targets(target configinternal.Target, event *Event) bool {
+	tags := selector.Set(event.tags)
+	if !tags.HasAll(target.GetTags()) {
+		return false
+	}
+
+	if target.Expression != nil && !target.Expression.Matches(tags) {
+		return false
+	}
+
+	switch target := target.Event.(type) {
+	case config.At:
+		return target.Time.UnixMilli() == event.Time.UnixMilli()
+	case configinternal.At:
+		return target.Time.UnixMilli() == event.Time.UnixMilli()
+	default:
+		return true
+	}
+}
+
+// sameTags reports whether a and b contain the same tags.
+func sameTags(a, b []string) bool {
+	return selector.Set(a).HasAll(b) && selector.Set(b).HasAll(a)
 }
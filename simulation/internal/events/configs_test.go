@@ -41,6 +41,19 @@ func Test_Configs_Add(t *testing.T) {
 	require.Len(t, e.configsByTags.All(), 1)
 	require.Len(t, e.configsByTagsAndTime, 0)
 
+	e.Set(config.Config{Selector: "test1 &&"})
+	e.Set(config.Config{Tags: []string{"test3"}, WaitFor: []config.Event{config.All{Selector: "(("}}})
+	require.Len(t, e.configsByTags.All(), 1)
+	require.Empty(t, e.selectorConfigs)
+	require.Len(t, e.invalid, 2)
+	require.Equal(t, config.ProblemInvalidSelector, e.invalid[0].Kind)
+	require.Equal(t, config.ProblemInvalidSelector, e.invalid[1].Kind)
+
+	e.Set(config.Config{Selector: "test1 && !test2"})
+	e.Set(config.Config{Selector: "test1 && !test2"})
+	require.Len(t, e.configsByTags.All(), 1)
+	require.Len(t, e.selectorConfigs, 1)
+
 	now := time.Now()
 
 	e.Set(config.Config{Time: now, Tags: []string{"test1", "test2"}})
@@ -148,7 +161,7 @@ func Test_Configs_BlockingEvents(t *testing.T) {
 			)
 
 			blockingEvents := e.BlockingEvents(mockEvent)
-			require.Equal(t, tt.wantBlockingEvents, blockingEvents)
+			require.Equal(t, tt.wantBlockingEvents, configinternal.Events(blockingEvents))
 		})
 	}
 }
@@ -158,6 +171,7 @@ func Test_Configs_ExpectedGenerators(t *testing.T) {
 	testcases := []struct {
 		name                   string
 		insertConfigs          []config.Config
+		resumption             bool
 		wantExpectedGenerators []*config.Generator
 	}{
 		{
@@ -165,16 +179,27 @@ func Test_Configs_ExpectedGenerators(t *testing.T) {
 			insertConfigs: []config.Config{
 				{
 					Tags: []string{"test1", "test2"},
-					Adds: []*config.Generator{{[]string{"testWanted"}, 1}},
+					Adds: []*config.Generator{{Tags: []string{"testWanted"}, Count: 1}},
 				},
 			},
-			wantExpectedGenerators: []*config.Generator{{[]string{"testWanted"}, 1}},
+			wantExpectedGenerators: []*config.Generator{{Tags: []string{"testWanted"}, Count: 1}},
 		},
 		{
 			name:                   "no config for event",
 			insertConfigs:          []config.Config{},
 			wantExpectedGenerators: nil,
 		},
+		{
+			name: "resumption",
+			insertConfigs: []config.Config{
+				{
+					Tags: []string{"test1", "test2"},
+					Adds: []*config.Generator{{Tags: []string{"testWanted"}, Count: 1}},
+				},
+			},
+			resumption:             true,
+			wantExpectedGenerators: nil,
+		},
 	}
 
 	for _, tt := range testcases {
@@ -192,6 +217,7 @@ func Test_Configs_ExpectedGenerators(t *testing.T) {
 				time.Time{},
 				[]string{"test1", "test2"},
 			)
+			mockEvent.resumption = tt.resumption
 
 			wantedNewGenerators := e.ExpectedGenerators(mockEvent)
 			require.Equal(t, tt.wantExpectedGenerators, wantedNewGenerators)
@@ -199,18 +225,85 @@ func Test_Configs_ExpectedGenerators(t *testing.T) {
 	}
 }
 
+func Test_Configs_Duration(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name          string
+		insertConfigs []config.Config
+		resumption    bool
+		wantDuration  time.Duration
+	}{
+		{
+			name: "valid config",
+			insertConfigs: []config.Config{
+				{
+					Tags:     []string{"test1", "test2"},
+					Duration: config.Fixed(time.Minute),
+				},
+			},
+			wantDuration: time.Minute,
+		},
+		{
+			name: "no duration configured",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test2"}},
+			},
+			wantDuration: 0,
+		},
+		{
+			name:          "no config for event",
+			insertConfigs: []config.Config{},
+			wantDuration:  0,
+		},
+		{
+			name: "resumption",
+			insertConfigs: []config.Config{
+				{
+					Tags:     []string{"test1", "test2"},
+					Duration: config.Fixed(time.Minute),
+				},
+			},
+			resumption:   true,
+			wantDuration: 0,
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := NewConfigs()
+			for _, configToInsert := range tt.insertConfigs {
+				e.Set(configToInsert)
+			}
+
+			mockEvent := NewEvent(
+				context.Background(),
+				timestone.NewMockAction(t),
+				time.Time{},
+				[]string{"test1", "test2"},
+			)
+			mockEvent.resumption = tt.resumption
+
+			require.Equal(t, tt.wantDuration, e.Duration(mockEvent))
+		})
+	}
+}
+
 func Test_Configs_configsByTagsForTime(t *testing.T) {
 	t.Parallel()
 
 	testcases := []struct {
 		name                 string
 		configsByTagsAndTime map[int64]*data.TaggedStore[*config.Config]
+		want                 *data.TaggedStore[*config.Config]
 	}{
 		{
 			name: "entry exists",
 			configsByTagsAndTime: map[int64]*data.TaggedStore[*config.Config]{
-				0: data.NewTaggedStore[*config.Config](),
+				time.Time{}.UnixMilli(): data.NewTaggedStore[*config.Config](),
 			},
+			want: data.NewTaggedStore[*config.Config](),
 		},
 		{
 			name:                 "entry does not exist",
@@ -226,7 +319,8 @@ func Test_Configs_configsByTagsForTime(t *testing.T) {
 			e.configsByTagsAndTime = tt.configsByTagsAndTime
 
 			result := e.configsByTagsForTime(time.Time{})
-			require.Equal(t, data.NewTaggedStore[*config.Config](), result)
+			require.Equal(t, tt.want, result)
+			require.Len(t, e.configsByTagsAndTime, len(tt.configsByTagsAndTime))
 		})
 	}
 }
@@ -289,3 +383,161 @@ func Test_Configs_get(t *testing.T) {
 		})
 	}
 }
+
+func Test_Configs_get_specificity(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name              string
+		insertConfigs     []config.Config
+		strict            bool
+		wantConfiguration *config.Config
+		wantAmbiguous     bool
+	}{
+		{
+			name: "subset of tags",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1"}, Priority: 10},
+			},
+			wantConfiguration: &config.Config{Tags: []string{"test1"}, Priority: 10},
+		},
+		{
+			name: "tags not contained",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "other"}, Priority: 10},
+			},
+			wantConfiguration: nil,
+		},
+		{
+			name: "more tags win",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test2"}, Priority: 20},
+				{Tags: []string{"test1"}, Priority: 10},
+			},
+			wantConfiguration: &config.Config{Tags: []string{"test1", "test2"}, Priority: 20},
+		},
+		{
+			name: "time wins over more tags",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test2", "test3"}, Priority: 20},
+				{Tags: []string{"test1"}, Time: time.Time{}.Add(1), Priority: 10},
+			},
+			wantConfiguration: &config.Config{Tags: []string{"test1"}, Time: time.Time{}.Add(1), Priority: 10},
+		},
+		{
+			name: "tie resolved by last config set",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test3"}, Priority: 10},
+				{Tags: []string{"test1", "test2"}, Priority: 20},
+			},
+			wantConfiguration: &config.Config{Tags: []string{"test1", "test2"}, Priority: 20},
+		},
+		{
+			name: "tie resolved by last config set, replaced",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test3"}, Priority: 10},
+				{Tags: []string{"test1", "test2"}, Priority: 20},
+				{Tags: []string{"test1", "test3"}, Priority: 30},
+			},
+			wantConfiguration: &config.Config{Tags: []string{"test1", "test3"}, Priority: 30},
+		},
+		{
+			name: "selector",
+			insertConfigs: []config.Config{
+				{Selector: "test1 && !other", Priority: 10},
+			},
+			wantConfiguration: &config.Config{Selector: "test1 && !other", Priority: 10},
+		},
+		{
+			name: "selector not matching",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1"}, Selector: "other || !test*", Priority: 10},
+			},
+			wantConfiguration: nil,
+		},
+		{
+			name: "selector terms count as tags",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test2"}, Priority: 10},
+				{Tags: []string{"test1"}, Selector: "test2 && test3", Priority: 20},
+			},
+			wantConfiguration: &config.Config{Tags: []string{"test1"}, Selector: "test2 && test3", Priority: 20},
+		},
+		{
+			name: "more tags win over selector",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test2", "test3"}, Priority: 10},
+				{Selector: "test*", Priority: 20},
+			},
+			wantConfiguration: &config.Config{Tags: []string{"test1", "test2", "test3"}, Priority: 10},
+		},
+		{
+			name: "selector replaced",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1"}, Selector: "test2", Priority: 10},
+				{Tags: []string{"test1"}, Selector: "test3", Priority: 20},
+				{Tags: []string{"test1"}, Selector: "test2", Priority: 30},
+			},
+			wantConfiguration: &config.Config{Tags: []string{"test1"}, Selector: "test2", Priority: 30},
+		},
+		{
+			name: "strict, tie with selector",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test2"}, Priority: 10},
+				{Selector: "test1 && test3", Priority: 20},
+			},
+			strict:            true,
+			wantConfiguration: &config.Config{Selector: "test1 && test3", Priority: 20},
+			wantAmbiguous:     true,
+		},
+		{
+			name: "strict, tie",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1", "test3"}, Priority: 10},
+				{Tags: []string{"test1", "test2"}, Priority: 20},
+			},
+			strict:            true,
+			wantConfiguration: &config.Config{Tags: []string{"test1", "test2"}, Priority: 20},
+			wantAmbiguous:     true,
+		},
+		{
+			name: "strict, tie of less specific configs",
+			insertConfigs: []config.Config{
+				{Tags: []string{"test1"}, Priority: 10},
+				{Tags: []string{"test2"}, Priority: 20},
+				{Tags: []string{"test1", "test2"}, Priority: 30},
+			},
+			strict:            true,
+			wantConfiguration: &config.Config{Tags: []string{"test1", "test2"}, Priority: 30},
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := NewConfigs()
+			e.SetStrict(tt.strict)
+			for _, configToInsert := range tt.insertConfigs {
+				e.Set(configToInsert)
+			}
+
+			mockEvent := NewEvent(
+				context.Background(),
+				timestone.NewMockAction(t),
+				time.Time{},
+				[]string{"test1", "test2", "test3"},
+			)
+
+			gotConfig := e.get(mockEvent)
+			require.Equal(t, tt.wantConfiguration, gotConfig)
+
+			if tt.wantAmbiguous {
+				require.Len(t, e.Ambiguous(), 1)
+				require.Equal(t, config.ProblemAmbiguous, e.Ambiguous()[0].Kind)
+			} else {
+				require.Empty(t, e.Ambiguous())
+			}
+		})
+	}
+}
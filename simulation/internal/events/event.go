@@ -17,6 +17,10 @@ type Event struct {
 	context.Context
 
 	tags []string
+
+	// resumption marks an Event that resumes an action parked by
+	// timestone.Sleep rather than starting a new execution.
+	resumption bool
 }
 
 func NewEvent(ctx context.Context, action timestone.Action, time time.Time, tags []string) *Event {
@@ -39,3 +43,9 @@ func NewEvent(ctx context.Context, action timestone.Action, time time.Time, tags
 func (e *Event) Tags() []string {
 	return e.tags
 }
+
+// Resumption reports whether e resumes an action parked by
+// timestone.Sleep rather than starting a new execution.
+func (e *Event) Resumption() bool {
+	return e.resumption
+}
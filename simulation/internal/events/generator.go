@@ -2,6 +2,7 @@ package events
 
 import (
 	"errors"
+	"time"
 )
 
 var ErrGeneratorFinished = errors.New("event generator is finished")
@@ -12,3 +13,38 @@ type Generator interface {
 
 	Finished() bool
 }
+
+// ResettableGenerator is a Generator whose next Event can be rescheduled,
+// as required to implement timestone.Handle.
+type ResettableGenerator interface {
+	Generator
+
+	// Reset reschedules the next Event to occur at from plus duration.
+	Reset(from time.Time, duration time.Duration)
+}
+
+// Previewer is implemented by Generator s that can tell the times of the
+// Event s they will materialize without materializing them.
+type Previewer interface {
+	// Preview returns the times of the Event s up to until, starting with
+	// the one Peek returns.
+	Preview(until time.Time) []time.Time
+}
+
+// Preview returns the times of the Event s of generator up to until. If
+// generator isn't a Previewer, only the time of its next Event is known.
+func Preview(generator Generator, until time.Time) []time.Time {
+	if generator.Finished() {
+		return nil
+	}
+
+	if previewer, ok := generator.(Previewer); ok {
+		return previewer.Preview(until)
+	}
+
+	if next := generator.Peek(); !next.Time.After(until) {
+		return []time.Time{next.Time}
+	}
+
+	return nil
+}
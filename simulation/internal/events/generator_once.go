@@ -8,17 +8,34 @@ import (
 )
 
 type OnceGenerator struct {
+	action     timestone.Action
+	tags       []string
+	resumption bool
+
 	event *Event
 	ctx   context.Context
 }
 
 func NewOnceGenerator(ctx context.Context, action timestone.Action, time time.Time, tags []string) *OnceGenerator {
 	return &OnceGenerator{
-		event: NewEvent(ctx, action, time, tags),
-		ctx:   ctx,
+		action: action,
+		tags:   tags,
+		event:  NewEvent(ctx, action, time, tags),
+		ctx:    ctx,
 	}
 }
 
+// NewResumptionGenerator returns a OnceGenerator for an Event resuming
+// an action that has been parked until time. Unlike other Event s,
+// resumptions don't expect added generators themselves.
+func NewResumptionGenerator(ctx context.Context, action timestone.Action, time time.Time, tags []string) *OnceGenerator {
+	generator := NewOnceGenerator(ctx, action, time, tags)
+	generator.resumption = true
+	generator.event.resumption = true
+
+	return generator
+}
+
 func (o *OnceGenerator) Pop() *Event {
 	if o.Finished() {
 		panic(ErrGeneratorFinished)
@@ -40,3 +57,8 @@ func (o *OnceGenerator) Peek() Event {
 func (o *OnceGenerator) Finished() bool {
 	return o.event == nil || o.ctx.Err() != nil
 }
+
+func (o *OnceGenerator) Reset(from time.Time, duration time.Duration) {
+	o.event = NewEvent(o.ctx, o.action, from.Add(duration), o.tags)
+	o.event.resumption = o.resumption
+}
@@ -46,6 +46,8 @@ func Test_NewOnceGenerator(t *testing.T) {
 				tags:       []string{"test"},
 			},
 			want: &OnceGenerator{
+				action: timestone.NewMockAction(t),
+				tags:   []string{"test"},
 				event: &Event{
 					Context: ctx,
 					Action:  timestone.NewMockAction(t),
@@ -73,6 +75,18 @@ func Test_NewOnceGenerator(t *testing.T) {
 	}
 }
 
+func Test_NewResumptionGenerator(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	o := NewResumptionGenerator(context.Background(), timestone.NewMockAction(t), now, []string{"test"})
+	require.True(t, o.Pop().Resumption())
+
+	o.Reset(now, time.Minute)
+	require.True(t, o.Pop().Resumption())
+}
+
 func Test_OnceGenerator_Pop(t *testing.T) {
 	t.Parallel()
 
@@ -245,3 +259,19 @@ func Test_OnceGenerator_Finished(t *testing.T) {
 		})
 	}
 }
+
+func Test_OnceGenerator_Reset(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := timestone.NewMockAction(t)
+
+	o := NewOnceGenerator(context.Background(), action, now, []string{"test"})
+	o.Pop()
+	require.True(t, o.Finished())
+
+	o.Reset(now, time.Minute)
+
+	require.False(t, o.Finished())
+	require.Equal(t, *NewEvent(context.Background(), action, now.Add(time.Minute), []string{"test"}), o.Peek())
+}
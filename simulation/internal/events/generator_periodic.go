@@ -89,3 +89,31 @@ func (p *PeriodicGenerator) Finished() bool {
 
 	return p.nextEvent.Add(p.interval).After(*p.to)
 }
+
+// Preview implements Previewer.
+func (p *PeriodicGenerator) Preview(until time.Time) []time.Time {
+	if p.Finished() {
+		return nil
+	}
+
+	var result []time.Time
+	for next := p.nextEvent.Time; !next.After(until); next = next.Add(p.interval) {
+		if p.to != nil && next.Add(p.interval).After(*p.to) {
+			break
+		}
+
+		result = append(result, next)
+	}
+
+	return result
+}
+
+func (p *PeriodicGenerator) Reset(from time.Time, duration time.Duration) {
+	if duration <= 0 {
+		panic("interval must be greater than zero")
+	}
+
+	p.from = from
+	p.interval = duration
+	p.nextEvent = NewEvent(p.ctx, p.action, from.Add(duration), p.tags)
+}
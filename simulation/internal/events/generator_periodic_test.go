@@ -391,3 +391,33 @@ func Test_PeriodicGenerator_Finished(t *testing.T) {
 		})
 	}
 }
+
+func Test_PeriodicGenerator_Reset(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := timestone.NewMockAction(t)
+
+	p := NewPeriodicGenerator(context.Background(), action, now, nil, time.Minute, []string{"test"})
+	p.Pop()
+
+	p.Reset(now.Add(time.Minute), 10*time.Second)
+
+	require.Equal(t, now.Add(70*time.Second), p.Pop().Time)
+	require.Equal(t, now.Add(80*time.Second), p.Peek().Time)
+
+	require.Panics(t, func() { p.Reset(now, 0) })
+}
+
+func Test_PeriodicGenerator_Preview(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	to := now.Add(5 * time.Minute)
+
+	p := NewPeriodicGenerator(context.Background(), timestone.NewMockAction(t), now, &to, time.Minute, []string{"test"})
+
+	require.Equal(t, []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute), now.Add(3 * time.Minute)}, p.Preview(now.Add(3*time.Minute)))
+	require.Equal(t, []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute), now.Add(3 * time.Minute), now.Add(4 * time.Minute)}, p.Preview(now.Add(time.Hour)))
+	require.Empty(t, p.Preview(now))
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+)
+
+type ScheduleGenerator struct {
+	action   timestone.Action
+	schedule timestone.Schedule
+
+	tags []string
+
+	nextEvent *Event
+
+	ctx context.Context
+}
+
+func NewScheduleGenerator(
+	ctx context.Context,
+	action timestone.Action,
+	from time.Time,
+	schedule timestone.Schedule,
+	tags []string,
+) *ScheduleGenerator {
+	if action == nil {
+		panic("Action can't be nil")
+	}
+
+	if schedule == nil {
+		panic("schedule can't be nil")
+	}
+
+	generator := &ScheduleGenerator{
+		action:   action,
+		schedule: schedule,
+
+		tags: tags,
+
+		ctx: ctx,
+	}
+
+	generator.nextEvent = generator.eventAfter(from)
+
+	return generator
+}
+
+func (s *ScheduleGenerator) Pop() *Event {
+	if s.Finished() {
+		panic(ErrGeneratorFinished)
+	}
+
+	defer func() { s.nextEvent = s.eventAfter(s.nextEvent.Time) }()
+
+	return s.nextEvent
+}
+
+func (s *ScheduleGenerator) Peek() Event {
+	if s.Finished() {
+		panic(ErrGeneratorFinished)
+	}
+
+	return *s.nextEvent
+}
+
+func (s *ScheduleGenerator) Finished() bool {
+	return s.nextEvent == nil || s.ctx.Err() != nil
+}
+
+// Preview implements Previewer.
+func (s *ScheduleGenerator) Preview(until time.Time) []time.Time {
+	if s.Finished() {
+		return nil
+	}
+
+	var result []time.Time
+	for next := s.nextEvent.Time; !next.After(until); {
+		result = append(result, next)
+
+		after, ok := s.schedule.Next(next)
+		if !ok || !after.After(next) {
+			break
+		}
+		next = after
+	}
+
+	return result
+}
+
+func (s *ScheduleGenerator) Reset(from time.Time, duration time.Duration) {
+	s.nextEvent = NewEvent(s.ctx, s.action, from.Add(duration), s.tags)
+}
+
+func (s *ScheduleGenerator) eventAfter(after time.Time) *Event {
+	next, ok := s.schedule.Next(after)
+	if !ok {
+		return nil
+	}
+
+	if !next.After(after) {
+		panic(fmt.Sprintf("schedule returned %v which is not after %v", next, after))
+	}
+
+	return NewEvent(s.ctx, s.action, next, s.tags)
+}
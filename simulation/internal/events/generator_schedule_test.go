@@ -0,0 +1,217 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/cron"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewScheduleGenerator(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		action   timestone.Action
+		from     time.Time
+		schedule timestone.Schedule
+		tags     []string
+	}
+
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	hourly := cron.MustParse("@hourly")
+
+	tests := []struct {
+		name         string
+		args         args
+		want         *ScheduleGenerator
+		requirePanic bool
+	}{
+		{
+			name: "no Action",
+			args: args{
+				action:   nil,
+				from:     now,
+				schedule: hourly,
+			},
+			requirePanic: true,
+		},
+		{
+			name: "no schedule",
+			args: args{
+				action:   timestone.NewMockAction(t),
+				from:     now,
+				schedule: nil,
+			},
+			requirePanic: true,
+		},
+		{
+			name: "schedule never fires",
+			args: args{
+				action:   timestone.NewMockAction(t),
+				from:     now,
+				schedule: cron.MustParse("0 0 30 2 *"),
+				tags:     []string{"test"},
+			},
+			want: &ScheduleGenerator{
+				action:   timestone.NewMockAction(t),
+				schedule: cron.MustParse("0 0 30 2 *"),
+				tags:     []string{"test"},
+				ctx:      ctx,
+			},
+		},
+		{
+			name: "success",
+			args: args{
+				action:   timestone.NewMockAction(t),
+				from:     now,
+				schedule: hourly,
+				tags:     []string{"test"},
+			},
+			want: &ScheduleGenerator{
+				action:   timestone.NewMockAction(t),
+				schedule: hourly,
+				tags:     []string{"test"},
+				nextEvent: &Event{
+					Action:  timestone.NewMockAction(t),
+					Time:    now.Add(time.Hour),
+					Context: ctx,
+					tags:    []string{"test"},
+				},
+				ctx: ctx,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.requirePanic {
+				require.Panics(t, func() {
+					_ = NewScheduleGenerator(ctx, tt.args.action, tt.args.from, tt.args.schedule, tt.args.tags)
+				})
+				return
+			}
+
+			newGenerator := NewScheduleGenerator(ctx, tt.args.action, tt.args.from, tt.args.schedule, tt.args.tags)
+			require.Equal(t, tt.want, newGenerator)
+		})
+	}
+}
+
+func Test_ScheduleGenerator_Pop(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewScheduleGenerator(context.Background(), timestone.NewMockAction(t), now, cron.MustParse("0 0 1 1 *"), []string{"test"})
+
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), c.Pop().Time)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), c.Pop().Time)
+	require.False(t, c.Finished())
+
+	c.nextEvent = nil
+	require.Panics(t, func() { _ = c.Pop() })
+}
+
+func Test_ScheduleGenerator_Pop_scheduleNotAdvancing(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewScheduleGenerator(context.Background(), timestone.NewMockAction(t), now, stuckSchedule{at: now.Add(time.Minute)}, []string{"test"})
+
+	require.Panics(t, func() { _ = c.Pop() })
+}
+
+type stuckSchedule struct {
+	at time.Time
+}
+
+func (s stuckSchedule) Next(time.Time) (time.Time, bool) {
+	return s.at, true
+}
+
+func Test_ScheduleGenerator_Peek(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewScheduleGenerator(context.Background(), timestone.NewMockAction(t), now, cron.MustParse("30 * * * *"), []string{"test"})
+
+	require.Equal(t, now.Add(30*time.Minute), c.Peek().Time)
+	require.Equal(t, now.Add(30*time.Minute), c.Peek().Time)
+
+	c.nextEvent = nil
+	require.Panics(t, func() { _ = c.Peek() })
+}
+
+func Test_ScheduleGenerator_Preview(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewScheduleGenerator(context.Background(), timestone.NewMockAction(t), now, cron.MustParse("30 * * * *"), []string{"test"})
+
+	require.Equal(t, []time.Time{now.Add(30 * time.Minute), now.Add(90 * time.Minute)}, c.Preview(now.Add(2*time.Hour)))
+	require.Empty(t, c.Preview(now))
+
+	c.nextEvent = nil
+	require.Empty(t, c.Preview(now.Add(2*time.Hour)))
+}
+
+func Test_ScheduleGenerator_Finished(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		generator *ScheduleGenerator
+		want      bool
+	}{
+		{
+			name:      "context is done",
+			generator: NewScheduleGenerator(cancelledCtx, timestone.NewMockAction(t), now, cron.MustParse("@hourly"), nil),
+			want:      true,
+		},
+		{
+			name:      "schedule never fires",
+			generator: NewScheduleGenerator(context.Background(), timestone.NewMockAction(t), now, cron.MustParse("0 0 30 2 *"), nil),
+			want:      true,
+		},
+		{
+			name:      "not finished",
+			generator: NewScheduleGenerator(context.Background(), timestone.NewMockAction(t), now, cron.MustParse("@hourly"), nil),
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tt.generator.Finished())
+		})
+	}
+}
+
+func Test_ScheduleGenerator_Reset(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduleGenerator(context.Background(), timestone.NewMockAction(t), now, cron.MustParse("@hourly"), []string{"test"})
+
+	s.Reset(now, 10*time.Minute)
+
+	require.Equal(t, now.Add(10*time.Minute), s.Pop().Time)
+	require.Equal(t, now.Add(time.Hour), s.Peek().Time)
+}
@@ -2,14 +2,18 @@ package events
 
 import (
 	"github.com/metamogul/timestone/v2/simulation/config"
+	configinternal "github.com/metamogul/timestone/v2/simulation/internal/config"
 	"github.com/metamogul/timestone/v2/simulation/internal/waitgroups"
 	"slices"
+	"time"
 )
 
 type Queue struct {
 	configs            *Configs
 	activeGenerators   []Generator
 	finishedGenerators []Generator
+	tieBreaker         tieBreaker
+	chooser            Chooser
 
 	NewGeneratorsWaitGroups *waitgroups.GeneratorWaitGroups
 }
@@ -25,6 +29,36 @@ func NewQueue(configs *Configs) *Queue {
 	return queue
 }
 
+// SetTieBreak sets how the Queue orders simultaneous Event s with the
+// same priority, with seed determining the random ranks of
+// TieBreakRandom.
+func (q *Queue) SetTieBreak(tieBreak TieBreak, seed uint64) {
+	q.tieBreaker = newTieBreaker(tieBreak, seed)
+
+	for _, generator := range q.activeGenerators {
+		q.tieBreaker.add(generator)
+	}
+
+	q.sortActiveGenerators()
+}
+
+// Chooser decides which of the simultaneous Event s with the same
+// priority at the head of a Queue is popped next.
+type Chooser interface {
+	// Choose returns the index of the Event to pop among candidates. It
+	// is called for every Event popped, with a single candidate if there
+	// is no choice.
+	Choose(candidates []Event) int
+}
+
+// SetChooser makes the Queue let chooser decide which Event to pop,
+// rather than popping the one sorted first. Of simultaneous Event s with
+// the same tags, only the first one is a candidate, and Event s waiting
+// for another candidate aren't.
+func (q *Queue) SetChooser(chooser Chooser) {
+	q.chooser = chooser
+}
+
 func (q *Queue) Add(generator Generator) {
 	if generator.Finished() {
 		q.finishedGenerators = append(q.finishedGenerators, generator)
@@ -32,6 +66,7 @@ func (q *Queue) Add(generator Generator) {
 	}
 
 	q.activeGenerators = append(q.activeGenerators, generator)
+	q.tieBreaker.add(generator)
 
 	generatorEventTags := generator.Peek().tags
 	q.NewGeneratorsWaitGroups.Done(generatorEventTags)
@@ -45,9 +80,11 @@ func (q *Queue) ExpectGenerators(expectedGenerators []*config.Generator) {
 	}
 }
 
-func (q *Queue) WaitForExpectedGenerators(expectedGenerators []*config.Generator) {
+// WaitForExpectedGenerators blocks until expectedGenerators have been
+// added, or stop is closed.
+func (q *Queue) WaitForExpectedGenerators(expectedGenerators []*config.Generator, stop <-chan struct{}) {
 	for _, expectedGenerator := range expectedGenerators {
-		q.NewGeneratorsWaitGroups.WaitFor(expectedGenerator.Tags)
+		q.NewGeneratorsWaitGroups.WaitFor(expectedGenerator.Tags, stop)
 	}
 }
 
@@ -56,11 +93,17 @@ func (q *Queue) Pop() *Event {
 		panic(ErrGeneratorFinished)
 	}
 
-	nextEvent := q.activeGenerators[0].Pop()
+	index := 0
+	if q.chooser != nil {
+		index = q.choose()
+	}
+
+	generator := q.activeGenerators[index]
+	nextEvent := generator.Pop()
 
-	if q.activeGenerators[0].Finished() {
-		q.finishedGenerators = append(q.finishedGenerators, q.activeGenerators[0])
-		q.activeGenerators = q.activeGenerators[1:]
+	if generator.Finished() {
+		q.finish(generator)
+		q.activeGenerators = slices.Delete(q.activeGenerators, index, index+1)
 	}
 
 	q.sortActiveGenerators()
@@ -76,11 +119,170 @@ func (q *Queue) Peek() Event {
 	return q.activeGenerators[0].Peek()
 }
 
+// Queued returns the next Event s of the generators in the Queue that
+// target refers to.
+func (q *Queue) Queued(target configinternal.Target) []Event {
+	var result []Event
+
+	for _, generator := range q.activeGenerators {
+		if generator.Finished() {
+			continue
+		}
+
+		if event := generator.Peek(); Targets(target, &event) {
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
+
+// Generators returns the generators in the Queue that haven't finished,
+// in the order their next Event s will be popped unless a Chooser
+// decides otherwise.
+func (q *Queue) Generators() []Generator {
+	q.removeFinishedGenerators()
+
+	return slices.Clone(q.activeGenerators)
+}
+
+// Upcoming returns the Event s the generators in the Queue will
+// materialize up to until, ordered by time. Only the next Event of
+// generators that aren't a Previewer is known.
+func (q *Queue) Upcoming(until time.Time) []Event {
+	var result []Event
+
+	for _, generator := range q.Generators() {
+		next := generator.Peek()
+
+		for _, at := range Preview(generator, until) {
+			event := next
+			event.Time = at
+			result = append(result, event)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b Event) int { return a.Time.Compare(b.Time) })
+
+	return result
+}
+
 func (q *Queue) Finished() bool {
+	q.removeFinishedGenerators()
+
 	return len(q.activeGenerators) == 0
 }
 
+// Update moves generator to the active or finished generators according
+// to its state, after it has been modified e.g. by
+// ResettableGenerator.Reset. Like Add, it fulfills expected generators
+// if generator is active.
+func (q *Queue) Update(generator Generator) {
+	isGenerator := func(g Generator) bool { return g == generator }
+	q.activeGenerators = slices.DeleteFunc(q.activeGenerators, isGenerator)
+	q.finishedGenerators = slices.DeleteFunc(q.finishedGenerators, isGenerator)
+
+	if generator.Finished() {
+		q.finish(generator)
+		return
+	}
+
+	q.activeGenerators = append(q.activeGenerators, generator)
+	q.tieBreaker.add(generator)
+
+	// Rescheduling the resumption of a parked action doesn't count as
+	// adding a generator, unlike adding it in the first place.
+	if nextEvent := generator.Peek(); !nextEvent.resumption {
+		q.NewGeneratorsWaitGroups.Done(nextEvent.tags)
+	}
+
+	q.sortActiveGenerators()
+}
+
+// choose returns the index of the active generator whose Event is to be
+// popped next, as decided by the Chooser.
+func (q *Queue) choose() int {
+	head := q.activeGenerators[0].Peek()
+	headPriority := q.configs.Priority(&head)
+
+	var candidates []Event
+	var indices []int
+	for i, generator := range q.activeGenerators {
+		event := generator.Peek()
+		if !event.Time.Equal(head.Time) || q.configs.Priority(&event) != headPriority {
+			break
+		}
+
+		if slices.ContainsFunc(candidates, func(candidate Event) bool { return sameTags(candidate.tags, event.tags) }) {
+			continue
+		}
+
+		candidates = append(candidates, event)
+		indices = append(indices, i)
+	}
+
+	// An Event waiting for another candidate must not start before it.
+	var unblockedCandidates []Event
+	var unblockedIndices []int
+	for i, candidate := range candidates {
+		blocked := slices.ContainsFunc(q.configs.BlockingEvents(&candidate), func(blockingEvent configinternal.Target) bool {
+			return slices.ContainsFunc(candidates, func(other Event) bool {
+				return !sameTags(other.tags, candidate.tags) && Targets(blockingEvent, &other)
+			})
+		})
+
+		if !blocked {
+			unblockedCandidates = append(unblockedCandidates, candidate)
+			unblockedIndices = append(unblockedIndices, indices[i])
+		}
+	}
+
+	// Candidates waiting for each other will block anyway.
+	if len(unblockedCandidates) > 0 {
+		candidates, indices = unblockedCandidates, unblockedIndices
+	}
+
+	// The order in which generators have been added might vary, so the
+	// candidates are presented in an order that doesn't.
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return slices.Compare(candidates[a].tags, candidates[b].tags)
+	})
+
+	sortedCandidates := make([]Event, len(candidates))
+	for i, j := range order {
+		sortedCandidates[i] = candidates[j]
+	}
+
+	return indices[order[q.chooser.Choose(sortedCandidates)]]
+}
+
+// removeFinishedGenerators moves active generators that have finished
+// without being popped, e.g. because their context was cancelled, to the
+// finished generators.
+func (q *Queue) removeFinishedGenerators() {
+	q.activeGenerators = slices.DeleteFunc(q.activeGenerators, func(generator Generator) bool {
+		if !generator.Finished() {
+			return false
+		}
+
+		q.finish(generator)
+		return true
+	})
+}
+
+// finish moves generator to the finished generators.
+func (q *Queue) finish(generator Generator) {
+	q.finishedGenerators = append(q.finishedGenerators, generator)
+	q.tieBreaker.remove(generator)
+}
+
 func (q *Queue) sortActiveGenerators() {
+	q.removeFinishedGenerators()
+
 	slices.SortStableFunc(q.activeGenerators, func(a, b Generator) int {
 		eventA, eventB := a.Peek(), b.Peek()
 
@@ -88,8 +290,10 @@ func (q *Queue) sortActiveGenerators() {
 			return timeComparison
 		}
 
-		priorityComparison := q.configs.Priority(&eventA) - q.configs.Priority(&eventB)
+		if priorityComparison := q.configs.Priority(&eventA) - q.configs.Priority(&eventB); priorityComparison != 0 {
+			return priorityComparison
+		}
 
-		return priorityComparison
+		return q.tieBreaker.compare(a, b, &eventA, &eventB)
 	})
 }
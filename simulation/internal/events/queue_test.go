@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"github.com/metamogul/timestone/v2/simulation/config"
+	configinternal "github.com/metamogul/timestone/v2/simulation/internal/config"
 	"slices"
 	"testing"
 	"time"
@@ -56,7 +57,7 @@ func TestQueue_Add(t *testing.T) {
 				mockEventGenerator.EXPECT().
 					Finished().
 					Return(false).
-					Once()
+					Twice()
 				mockEventGenerator.EXPECT().
 					Peek().
 					Return(
@@ -88,7 +89,7 @@ func TestQueue_Add(t *testing.T) {
 				require.Len(t, e.finishedGenerators, 0)
 				e.NewGeneratorsWaitGroups.Add(1, []string{"test"})
 				go func() { e.NewGeneratorsWaitGroups.Done([]string{"test"}) }()
-				e.NewGeneratorsWaitGroups.WaitFor([]string{"test"})
+				e.NewGeneratorsWaitGroups.WaitFor([]string{"test"}, nil)
 			} else {
 				require.Len(t, e.activeGenerators, 0)
 				require.Len(t, e.finishedGenerators, 1)
@@ -111,7 +112,7 @@ func TestQueue_ExpectGenerators(t *testing.T) {
 	generatorMock.EXPECT().
 		Finished().
 		Return(false).
-		Once()
+		Twice()
 	generatorMock.EXPECT().
 		Peek().
 		Return(
@@ -130,7 +131,7 @@ func TestQueue_ExpectGenerators(t *testing.T) {
 
 	e.ExpectGenerators(generatorExpectations)
 	go func() { e.Add(generatorMock) }()
-	e.WaitForExpectedGenerators(generatorExpectations)
+	e.WaitForExpectedGenerators(generatorExpectations, nil)
 }
 
 func TestQueue_WaitForExpectedGenerators(t *testing.T) {
@@ -142,7 +143,7 @@ func TestQueue_WaitForExpectedGenerators(t *testing.T) {
 	generatorMock.EXPECT().
 		Finished().
 		Return(false).
-		Once()
+		Twice()
 	generatorMock.EXPECT().
 		Peek().
 		Return(
@@ -161,7 +162,7 @@ func TestQueue_WaitForExpectedGenerators(t *testing.T) {
 
 	e.ExpectGenerators(generatorExpectations)
 	go func() { e.Add(generatorMock) }()
-	e.WaitForExpectedGenerators(generatorExpectations)
+	e.WaitForExpectedGenerators(generatorExpectations, nil)
 }
 
 func TestQueue_Pop(t *testing.T) {
@@ -355,11 +356,35 @@ func TestQueue_Finished(t *testing.T) {
 		{
 			name: "not finished",
 			fields: fields{
-				activeGenerators:   []Generator{NewMockGenerator(t)},
+				activeGenerators: func() []Generator {
+					mockEventGenerator := NewMockGenerator(t)
+					mockEventGenerator.EXPECT().
+						Finished().
+						Return(false).
+						Once()
+
+					return []Generator{mockEventGenerator}
+				}(),
 				finishedGenerators: make([]Generator, 0),
 			},
 			want: false,
 		},
+		{
+			name: "active generator finished meanwhile",
+			fields: fields{
+				activeGenerators: func() []Generator {
+					mockEventGenerator := NewMockGenerator(t)
+					mockEventGenerator.EXPECT().
+						Finished().
+						Return(true).
+						Once()
+
+					return []Generator{mockEventGenerator}
+				}(),
+				finishedGenerators: make([]Generator, 0),
+			},
+			want: true,
+		},
 		{
 			name: "finished",
 			fields: fields{
@@ -404,3 +429,243 @@ func TestQueue_sortActiveGenerators(t *testing.T) {
 	})
 	require.True(t, sorted)
 }
+
+func TestQueue_SetTieBreak(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		tieBreak TieBreak
+		want     []string
+	}{
+		{
+			name:     "FIFO",
+			tieBreak: TieBreakFIFO,
+			want:     []string{"b", "c", "a", "d"},
+		},
+		{
+			name:     "LIFO",
+			tieBreak: TieBreakLIFO,
+			want:     []string{"b", "a", "c", "d"},
+		},
+		{
+			name:     "lexical",
+			tieBreak: TieBreakLexical,
+			want:     []string{"b", "a", "c", "d"},
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, popTags(newTieBreakQueue(t, tt.tieBreak, 0)))
+		})
+	}
+}
+
+func TestQueue_SetTieBreak_random(t *testing.T) {
+	t.Parallel()
+
+	orders := make(map[string]bool)
+	for seed := range uint64(20) {
+		order := popTags(newTieBreakQueue(t, TieBreakRandom, seed))
+		require.Equal(t, order, popTags(newTieBreakQueue(t, TieBreakRandom, seed)))
+		require.Equal(t, "b", order[0])
+		require.Equal(t, "d", order[3])
+
+		orders[order[1]+order[2]] = true
+	}
+
+	require.Len(t, orders, 2)
+}
+
+func TestQueue_SetTieBreak_randomAddOrder(t *testing.T) {
+	t.Parallel()
+
+	for seed := range uint64(20) {
+		require.Equal(t,
+			popTags(newTieBreakQueue(t, TieBreakRandom, seed)),
+			popTags(newTieBreakQueue(t, TieBreakRandom, seed, "a", "b", "c")),
+		)
+	}
+}
+
+// newTieBreakQueue returns a Queue with simultaneous events tagged c and
+// a after one tagged b with a higher priority, followed by one tagged d.
+// The simultaneous events are added in the order of tags if given.
+func newTieBreakQueue(t *testing.T, tieBreak TieBreak, seed uint64, tags ...string) *Queue {
+	configs := NewConfigs()
+	configs.Set(config.Config{Tags: []string{"b"}, Priority: -1})
+
+	q := NewQueue(configs)
+	q.SetTieBreak(tieBreak, seed)
+
+	if len(tags) == 0 {
+		tags = []string{"b", "c", "a"}
+	}
+
+	for _, tag := range tags {
+		q.Add(NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}, []string{tag}))
+	}
+	q.Add(NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(time.Second), []string{"d"}))
+
+	return q
+}
+
+func popTags(q *Queue) []string {
+	var result []string
+	for !q.Finished() {
+		result = append(result, q.Pop().Tags()...)
+	}
+
+	return result
+}
+
+// lastChooser chooses the last candidate, recording their tags.
+type lastChooser struct {
+	candidates [][]string
+}
+
+func (l *lastChooser) Choose(candidates []Event) int {
+	var tags []string
+	for _, candidate := range candidates {
+		tags = append(tags, candidate.tags...)
+	}
+	l.candidates = append(l.candidates, tags)
+
+	return len(candidates) - 1
+}
+
+func TestQueue_SetChooser(t *testing.T) {
+	t.Parallel()
+
+	configs := NewConfigs()
+	configs.Set(config.Config{Tags: []string{"c"}, WaitFor: []config.Event{config.All{Tags: []string{"b"}}}})
+
+	q := NewQueue(configs)
+	chooser := &lastChooser{}
+	q.SetChooser(chooser)
+
+	for _, tag := range []string{"c", "a", "b", "a"} {
+		q.Add(NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}, []string{tag}))
+	}
+	q.Add(NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(time.Second), []string{"d"}))
+
+	require.Equal(t, []string{"b", "c", "a", "a", "d"}, popTags(q))
+	require.Equal(t, [][]string{{"a", "b"}, {"a", "c"}, {"a"}, {"a"}, {"d"}}, chooser.candidates)
+}
+
+func TestQueue_Queued(t *testing.T) {
+	t.Parallel()
+
+	q := newTieBreakQueue(t, TieBreakFIFO, 0)
+
+	target, err := configinternal.ParseTarget(config.All{Selector: "a || d"})
+	require.NoError(t, err)
+
+	queued := q.Queued(target)
+	require.Len(t, queued, 2)
+	require.Equal(t, []string{"a"}, queued[0].Tags())
+	require.Equal(t, []string{"d"}, queued[1].Tags())
+
+	require.Empty(t, q.Queued(configinternal.Target{Event: config.At{Time: time.Time{}, Tags: []string{"d"}}}))
+}
+
+func TestQueue_Upcoming(t *testing.T) {
+	t.Parallel()
+
+	q := NewQueue(NewConfigs())
+	q.Add(NewPeriodicGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}, nil, time.Minute, []string{"periodic"}))
+	q.Add(NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(90*time.Second), []string{"once"}))
+	q.Add(NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(time.Hour), []string{"later"}))
+
+	var tags []string
+	for _, event := range q.Upcoming(time.Time{}.Add(3 * time.Minute)) {
+		tags = append(tags, event.Tags()...)
+	}
+	require.Equal(t, []string{"periodic", "once", "periodic", "periodic"}, tags)
+
+	require.Len(t, q.Generators(), 3)
+	require.Equal(t, []string{"periodic"}, q.Generators()[0].Peek().tags)
+}
+
+func TestQueue_Update(t *testing.T) {
+	t.Parallel()
+
+	generator := NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(time.Minute), []string{"test1"})
+	otherGenerator := NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(time.Hour), []string{"test2"})
+
+	e := NewQueue(NewConfigs())
+	e.Add(generator)
+	e.Add(otherGenerator)
+
+	e.Pop()
+	require.Len(t, e.activeGenerators, 1)
+	require.Len(t, e.finishedGenerators, 1)
+
+	generator.Reset(time.Time{}.Add(2*time.Hour), 0)
+	e.Update(generator)
+	require.Equal(t, []Generator{otherGenerator, generator}, e.activeGenerators)
+	require.Len(t, e.finishedGenerators, 0)
+
+	generator.Reset(time.Time{}, 0)
+	e.Update(generator)
+	require.Equal(t, []Generator{generator, otherGenerator}, e.activeGenerators)
+
+	generator.Pop()
+	e.Update(generator)
+	require.Equal(t, []Generator{otherGenerator}, e.activeGenerators)
+	require.Equal(t, []Generator{generator}, e.finishedGenerators)
+}
+
+func TestQueue_Update_resumption(t *testing.T) {
+	t.Parallel()
+
+	generator := NewResumptionGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(time.Hour), []string{"test"})
+
+	e := NewQueue(NewConfigs())
+	e.ExpectGenerators([]*config.Generator{{Tags: []string{"test"}, Count: 2}})
+
+	e.Add(generator)
+	generator.Reset(time.Time{}, time.Minute)
+	e.Update(generator)
+
+	// Adding the resumption counts, rescheduling it doesn't.
+	waited := make(chan struct{})
+	go func() {
+		e.WaitForExpectedGenerators([]*config.Generator{{Tags: []string{"test"}, Count: 2}}, nil)
+		close(waited)
+	}()
+
+	require.Never(t, func() bool {
+		select {
+		case <-waited:
+			return true
+		default:
+			return false
+		}
+	}, 10*time.Millisecond, time.Millisecond)
+
+	e.Add(NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(time.Hour), []string{"test"}))
+	<-waited
+}
+
+func TestQueue_removeFinishedGenerators(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancelledGenerator := NewOnceGenerator(ctx, timestone.NewMockAction(t), time.Time{}, []string{"test1"})
+	generator := NewOnceGenerator(context.Background(), timestone.NewMockAction(t), time.Time{}.Add(time.Minute), []string{"test2"})
+
+	e := NewQueue(NewConfigs())
+	e.Add(cancelledGenerator)
+	e.Add(generator)
+
+	cancel()
+
+	require.Equal(t, generator.Peek(), e.Peek())
+	require.Equal(t, []Generator{generator}, e.activeGenerators)
+	require.Equal(t, []Generator{cancelledGenerator}, e.finishedGenerators)
+}
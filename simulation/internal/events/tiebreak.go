@@ -0,0 +1,91 @@
+package events
+
+import (
+	"cmp"
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+)
+
+// TieBreak determines the order of simultaneous Event s with the same
+// priority.
+type TieBreak int
+
+const (
+	// TieBreakFIFO keeps the order in which the generators of the Event s
+	// have been sorted into the Queue before.
+	TieBreakFIFO TieBreak = iota
+	// TieBreakLIFO orders the generators added to the Queue last first.
+	TieBreakLIFO
+	// TieBreakRandom orders the Event s randomly by a rank hashed from
+	// the seed, their tags and their time, so that their order doesn't
+	// depend on the order their generators have been added in.
+	TieBreakRandom
+	// TieBreakLexical orders the Event s lexically by their tags.
+	TieBreakLexical
+)
+
+// tieBreaker holds the state required to apply a TieBreak. Its zero
+// value applies TieBreakFIFO.
+type tieBreaker struct {
+	tieBreak TieBreak
+
+	seed uint64
+	// sequence records the order in which generators have been added for
+	// TieBreakLIFO.
+	sequence map[Generator]int
+	added    int
+}
+
+func newTieBreaker(tieBreak TieBreak, seed uint64) tieBreaker {
+	return tieBreaker{
+		tieBreak: tieBreak,
+		seed:     seed,
+		sequence: make(map[Generator]int),
+	}
+}
+
+// add records that generator has been added to the Queue.
+func (t *tieBreaker) add(generator Generator) {
+	if t.tieBreak == TieBreakLIFO {
+		t.added++
+		t.sequence[generator] = t.added
+	}
+}
+
+// remove forgets generator once it has finished.
+func (t *tieBreaker) remove(generator Generator) {
+	delete(t.sequence, generator)
+}
+
+// compare orders a and b, whose next Event s eventA and eventB are
+// simultaneous and have the same priority. It returns 0 to keep their
+// order.
+func (t *tieBreaker) compare(a, b Generator, eventA, eventB *Event) int {
+	switch t.tieBreak {
+	case TieBreakLIFO:
+		return cmp.Compare(t.sequence[b], t.sequence[a])
+	case TieBreakRandom:
+		return cmp.Compare(t.rank(eventA), t.rank(eventB))
+	case TieBreakLexical:
+		return slices.Compare(eventA.tags, eventB.tags)
+	default:
+		return 0
+	}
+}
+
+// rank returns the rank of event for TieBreakRandom.
+func (t *tieBreaker) rank(event *Event) uint64 {
+	hash := fnv.New64a()
+
+	b := binary.LittleEndian.AppendUint64(nil, t.seed)
+	b = binary.LittleEndian.AppendUint64(b, uint64(event.Time.Unix()))
+	b = binary.LittleEndian.AppendUint32(b, uint32(event.Time.Nanosecond()))
+	for _, tag := range event.tags {
+		b = append(b, tag...)
+		b = append(b, 0)
+	}
+	hash.Write(b)
+
+	return hash.Sum64()
+}
@@ -0,0 +1,354 @@
+package events
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/metamogul/timestone/v2/simulation/config"
+	configinternal "github.com/metamogul/timestone/v2/simulation/internal/config"
+	"github.com/metamogul/timestone/v2/simulation/internal/selector"
+)
+
+// dependency is an edge from a config.Config to one configuring events
+// that the events it configures may wait for at the same time.
+type dependency struct {
+	from, to *config.Config
+	target   config.Event
+}
+
+// Mark marks the configs set so far, so that Validate reports only the
+// problems involving configs set afterwards. Its zero value marks none.
+type Mark struct {
+	sequence, invalid int
+}
+
+// Mark returns a Mark of the configs set so far.
+func (c *Configs) Mark() Mark {
+	return Mark{sequence: len(c.sequence), invalid: len(c.invalid)}
+}
+
+// Validate returns the problems of the configs set after since that can
+// be told without executing any event, including those involving configs
+// set before since: selectors that can't be parsed, which
+// leave their config.Config unset, cycles of config.Config.WaitFor between
+// simultaneous events, config.At targets that can't exist when waited
+// for, config.Before with a positive Interval, and configs waiting for
+// simultaneous events with a greater priority.
+//
+// A config.Before with a zero Interval isn't reported: it is the only
+// way for a config.Config without a Time to wait for events simultaneous
+// with the one it configures, and is checked for cycles instead.
+//
+// As events are only known once they are executed, a config.Config is
+// considered to wait for another one if the events with exactly the tags
+// it waits for are configured by the other one.
+func (c *Configs) Validate(since Mark) []config.Problem {
+	configs := c.all()
+
+	problems := slices.Clone(c.invalid[since.invalid:])
+	var dependencies []dependency
+
+	for _, configuration := range configs {
+		report := c.setSince(since, configuration)
+
+		for _, target := range configuration.WaitFor {
+			switch target := target.(type) {
+			case config.Before:
+				if target.Interval > 0 && report {
+					problems = append(problems, config.Problem{
+						Kind:        config.ProblemPositiveBefore,
+						Configs:     []config.Config{*configuration},
+						Explanation: fmt.Sprintf("%s waits for %s, which is executed later", explain(configuration), explainTarget(target)),
+					})
+				}
+
+				// Only a Before without Interval refers to simultaneous
+				// events, which is fine unless they wait for each other.
+				if target.Interval != 0 {
+					continue
+				}
+
+			case config.At:
+				if target.Time.IsZero() {
+					if report {
+						problems = append(problems, config.Problem{
+							Kind:        config.ProblemUnreachableAt,
+							Configs:     []config.Config{*configuration},
+							Explanation: fmt.Sprintf("%s waits for %s, which has no Time", explain(configuration), explainTarget(target)),
+						})
+					}
+					continue
+				}
+
+				if !configuration.Time.IsZero() && target.Time.After(configuration.Time) {
+					if report {
+						problems = append(problems, config.Problem{
+							Kind:        config.ProblemUnreachableAt,
+							Configs:     []config.Config{*configuration},
+							Explanation: fmt.Sprintf("%s waits for %s, which is executed later", explain(configuration), explainTarget(target)),
+						})
+					}
+					continue
+				}
+			}
+
+			for _, other := range configs {
+				if c.mayWaitFor(configuration, target, other) {
+					dependencies = append(dependencies, dependency{from: configuration, to: other, target: target})
+				}
+			}
+		}
+	}
+
+	// Of configs set for different times, the one with a set Time might
+	// shadow another one for the events it waits for, so only configs
+	// for the same time are compared.
+	for _, d := range dependencies {
+		if d.from.Time.Equal(d.to.Time) && d.from.Priority < d.to.Priority && c.setSince(since, d.from, d.to) {
+			problems = append(problems, config.Problem{
+				Kind:    config.ProblemPriorityConflict,
+				Configs: []config.Config{*d.from, *d.to},
+				Explanation: fmt.Sprintf(
+					"%s with priority %d waits for %s, which may be %s with priority %d and is executed after it",
+					explain(d.from), d.from.Priority, explainTarget(d.target), explain(d.to), d.to.Priority,
+				),
+			})
+		}
+	}
+
+	for _, cycle := range c.cycles(configs, dependencies) {
+		problem := config.Problem{Kind: config.ProblemWaitForCycle}
+
+		steps := make([]string, len(cycle))
+		report := false
+		for i, d := range cycle {
+			problem.Configs = append(problem.Configs, *d.from)
+			steps[i] = fmt.Sprintf("%s waits for %s", explain(d.from), explainTarget(d.target))
+			report = report || c.setSince(since, d.from)
+		}
+		problem.Explanation = strings.Join(steps, ", ")
+
+		if report {
+			problems = append(problems, problem)
+		}
+	}
+
+	return problems
+}
+
+// setSince reports whether one of configs has been set after since.
+func (c *Configs) setSince(since Mark, configs ...*config.Config) bool {
+	return slices.ContainsFunc(configs, func(configuration *config.Config) bool {
+		return c.sequence[configuration] >= since.sequence
+	})
+}
+
+// Unmatched returns a problem for each config.Config that hasn't
+// configured any event, unless its Time is after until.
+func (c *Configs) Unmatched(until time.Time) []config.Problem {
+	c.matchedMu.Lock()
+	defer c.matchedMu.Unlock()
+
+	var problems []config.Problem
+
+	for _, configuration := range c.all() {
+		if c.matched[configuration] || configuration.Time.After(until) {
+			continue
+		}
+
+		problems = append(problems, config.Problem{
+			Kind:        config.ProblemUnmatched,
+			Configs:     []config.Config{*configuration},
+			Explanation: fmt.Sprintf("%s hasn't configured any event", explain(configuration)),
+		})
+	}
+
+	return problems
+}
+
+// Missed records that event hasn't waited for targets of the WaitFor of
+// its config.Config, because no event existed for them.
+func (c *Configs) Missed(event *Event, targets []configinternal.Target) {
+	configuration := c.lookup(event)
+	if configuration == nil {
+		return
+	}
+
+	c.matchedMu.Lock()
+	defer c.matchedMu.Unlock()
+
+	for _, target := range targets {
+		key := missedTarget{configuration: configuration, target: explainTarget(target.Event)}
+		if c.missedTargets[key] {
+			continue
+		}
+		c.missedTargets[key] = true
+
+		c.missed = append(c.missed, config.Problem{
+			Kind:    config.ProblemMissingTarget,
+			Configs: []config.Config{*configuration},
+			Explanation: fmt.Sprintf(
+				"%s waits for %s, but no such event existed when the event tagged %v at %v waited for it",
+				explain(configuration), key.target, event.tags, event.Time,
+			),
+		})
+	}
+}
+
+// Missing returns the problems recorded by Missed.
+func (c *Configs) Missing() []config.Problem {
+	c.matchedMu.Lock()
+	defer c.matchedMu.Unlock()
+
+	return slices.Clone(c.missed)
+}
+
+// tied records that event matches configuration and applied, which are
+// equally specific, and that applied has won the tie.
+func (c *Configs) tied(event *Event, configuration, applied *config.Config) {
+	c.matchedMu.Lock()
+	defer c.matchedMu.Unlock()
+
+	key := [2]*config.Config{configuration, applied}
+	if c.ambiguousConfigs[key] {
+		return
+	}
+	c.ambiguousConfigs[key] = true
+
+	c.ambiguous = append(c.ambiguous, config.Problem{
+		Kind:    config.ProblemAmbiguous,
+		Configs: []config.Config{*configuration, *applied},
+		Explanation: fmt.Sprintf(
+			"%s and %s are equally specific for the event tagged %v at %v, the latter has been applied",
+			explain(configuration), explain(applied), event.tags, event.Time,
+		),
+	})
+}
+
+// Ambiguous returns the problems recorded for ties between configs if
+// Configs is strict.
+func (c *Configs) Ambiguous() []config.Problem {
+	c.matchedMu.Lock()
+	defer c.matchedMu.Unlock()
+
+	return slices.Clone(c.ambiguous)
+}
+
+// all returns the configs set, in the order they have been set.
+func (c *Configs) all() []*config.Config {
+	result := append(c.configsByTags.All(), c.selectorConfigs...)
+	for _, configs := range c.configsByTagsAndTime {
+		result = append(result, configs.All()...)
+	}
+	for _, configs := range c.selectorConfigsByTime {
+		result = append(result, configs...)
+	}
+
+	slices.SortFunc(result, func(a, b *config.Config) int { return c.sequence[a] - c.sequence[b] })
+
+	return result
+}
+
+// mayWaitFor reports whether an event configured by configuration may wait
+// for a simultaneous event configured by other, because of target.
+func (c *Configs) mayWaitFor(configuration *config.Config, target config.Event, other *config.Config) bool {
+	at := configuration.Time
+	if target, ok := target.(config.At); ok {
+		if !at.IsZero() && !at.Equal(target.Time) {
+			return false
+		}
+		at = target.Time
+	}
+	if !at.IsZero() && !other.Time.IsZero() && !at.Equal(other.Time) {
+		return false
+	}
+
+	tags := selector.Set(target.GetTags())
+	if !tags.HasAll(other.Tags) {
+		return false
+	}
+
+	if expression := c.expressionsBySelectors[target.GetSelector()]; expression != nil && !expression.Matches(tags) {
+		return false
+	}
+
+	if expression := c.expressionsBySelectors[other.Selector]; expression != nil && !expression.Matches(tags) {
+		return false
+	}
+
+	return true
+}
+
+// cycles returns a cycle of dependencies for each config.Config that
+// depends on itself, and isn't part of a cycle found before.
+func (c *Configs) cycles(configs []*config.Config, dependencies []dependency) [][]dependency {
+	var result [][]dependency
+	inCycle := make(map[*config.Config]bool)
+
+	for _, start := range configs {
+		if inCycle[start] {
+			continue
+		}
+
+		cycle := findCycle(start, dependencies)
+		if cycle == nil {
+			continue
+		}
+
+		for _, d := range cycle {
+			inCycle[d.from] = true
+		}
+		result = append(result, cycle)
+	}
+
+	return result
+}
+
+// findCycle returns the shortest path of dependencies from start back
+// to itself, or nil if there is none.
+func findCycle(start *config.Config, dependencies []dependency) []dependency {
+	paths := map[*config.Config][]dependency{start: nil}
+	frontier := []*config.Config{start}
+
+	for len(frontier) > 0 {
+		var next []*config.Config
+
+		for _, from := range frontier {
+			for _, d := range dependencies {
+				if d.from != from {
+					continue
+				}
+
+				path := append(slices.Clone(paths[from]), d)
+				if d.to == start {
+					return path
+				}
+
+				if _, visited := paths[d.to]; !visited {
+					paths[d.to] = path
+					next = append(next, d.to)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return nil
+}
+
+// explain describes configuration for a config.Problem.
+func explain(configuration *config.Config) string {
+	if configuration.Time.IsZero() {
+		return "config " + describe(configuration)
+	}
+
+	return fmt.Sprintf("config %s at %v", describe(configuration), configuration.Time)
+}
+
+// explainTarget describes target for a config.Problem.
+func explainTarget(target config.Event) string {
+	return fmt.Sprintf("%T%+v", target, target)
+}
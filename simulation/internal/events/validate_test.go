@@ -0,0 +1,152 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Configs_Validate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name          string
+		configs       []config.Config
+		expectedKinds []config.ProblemKind
+	}{
+		{
+			name: "valid",
+			configs: []config.Config{
+				{Tags: []string{"b"}, Priority: 1, WaitFor: []config.Event{config.All{Tags: []string{"a"}}}},
+				{Tags: []string{"c"}, WaitFor: []config.Event{config.Before{Interval: -time.Minute, Tags: []string{"c"}}}},
+				{Tags: []string{"d"}, Time: now, WaitFor: []config.Event{config.At{Time: now.Add(-time.Minute), Tags: []string{"d"}}}},
+				{Tags: []string{"e"}, WaitFor: []config.Event{config.Before{Tags: []string{"a"}}}},
+			},
+		},
+		{
+			name: "waiting for itself",
+			configs: []config.Config{
+				{Tags: []string{"a"}, WaitFor: []config.Event{config.All{Tags: []string{"a", "b"}}}},
+			},
+			expectedKinds: []config.ProblemKind{config.ProblemWaitForCycle},
+		},
+		{
+			name: "cycle",
+			configs: []config.Config{
+				{Tags: []string{"a"}, WaitFor: []config.Event{config.All{Tags: []string{"b"}}}},
+				{Tags: []string{"b"}, WaitFor: []config.Event{config.Before{Tags: []string{"c"}}}},
+				{Tags: []string{"c"}, WaitFor: []config.Event{config.All{Tags: []string{"a"}}}},
+			},
+			expectedKinds: []config.ProblemKind{config.ProblemWaitForCycle},
+		},
+		{
+			name: "cycle with selectors",
+			configs: []config.Config{
+				{Selector: "shard=1", WaitFor: []config.Event{config.All{Tags: []string{"shard=2"}}}},
+				{Selector: "shard=2", WaitFor: []config.Event{config.All{Tags: []string{"shard=1"}}}},
+			},
+			expectedKinds: []config.ProblemKind{config.ProblemWaitForCycle},
+		},
+		{
+			name: "no cycle at different times",
+			configs: []config.Config{
+				{Tags: []string{"a"}, WaitFor: []config.Event{config.At{Time: now, Tags: []string{"b"}}}},
+				{Tags: []string{"b"}, Time: now.Add(time.Minute), WaitFor: []config.Event{config.All{Tags: []string{"a"}}}},
+			},
+		},
+		{
+			name: "At without Time",
+			configs: []config.Config{
+				{Tags: []string{"a"}, WaitFor: []config.Event{config.At{Tags: []string{"b"}}}},
+			},
+			expectedKinds: []config.ProblemKind{config.ProblemUnreachableAt},
+		},
+		{
+			name: "At after Time",
+			configs: []config.Config{
+				{Tags: []string{"a"}, Time: now, WaitFor: []config.Event{config.At{Time: now.Add(time.Minute), Tags: []string{"b"}}}},
+			},
+			expectedKinds: []config.ProblemKind{config.ProblemUnreachableAt},
+		},
+		{
+			name: "positive Before",
+			configs: []config.Config{
+				{Tags: []string{"a"}, WaitFor: []config.Event{config.Before{Interval: time.Minute, Tags: []string{"b"}}}},
+			},
+			expectedKinds: []config.ProblemKind{config.ProblemPositiveBefore},
+		},
+		{
+			name: "priority conflict",
+			configs: []config.Config{
+				{Tags: []string{"a"}, Priority: 2},
+				{Tags: []string{"b"}, Priority: 1, WaitFor: []config.Event{config.All{Tags: []string{"a"}}}},
+			},
+			expectedKinds: []config.ProblemKind{config.ProblemPriorityConflict},
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewConfigs()
+			for _, configuration := range tt.configs {
+				c.Set(configuration)
+			}
+
+			var kinds []config.ProblemKind
+			for _, problem := range c.Validate(Mark{}) {
+				kinds = append(kinds, problem.Kind)
+				require.NotEmpty(t, problem.Configs)
+				require.NotEmpty(t, problem.Explanation)
+			}
+			require.Equal(t, tt.expectedKinds, kinds)
+		})
+	}
+}
+
+func Test_Configs_Validate_since(t *testing.T) {
+	t.Parallel()
+
+	c := NewConfigs()
+	c.Set(config.Config{Tags: []string{"a"}, WaitFor: []config.Event{config.All{Tags: []string{"b"}}}})
+	c.Set(config.Config{Tags: []string{"c"}, WaitFor: []config.Event{config.Before{Interval: time.Minute, Tags: []string{"d"}}}})
+	c.Set(config.Config{Selector: "(("})
+
+	since := c.Mark()
+	c.Set(config.Config{Tags: []string{"d"}})
+	require.Empty(t, c.Validate(since))
+
+	c.Set(config.Config{Tags: []string{"b"}, WaitFor: []config.Event{config.All{Tags: []string{"a"}}}})
+	problems := c.Validate(since)
+	require.Len(t, problems, 1)
+	require.Equal(t, config.ProblemWaitForCycle, problems[0].Kind)
+
+	require.Len(t, c.Validate(Mark{}), 3)
+}
+
+func Test_Configs_Unmatched(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewConfigs()
+	c.Set(config.Config{Tags: []string{"matched"}})
+	c.Set(config.Config{Tags: []string{"unmatched"}})
+	c.Set(config.Config{Tags: []string{"later"}, Time: now.Add(time.Minute)})
+
+	event := NewEvent(context.Background(), timestone.NewMockAction(t), now, []string{"matched"})
+	c.Priority(event)
+
+	problems := c.Unmatched(now)
+	require.Len(t, problems, 1)
+	require.Equal(t, config.ProblemUnmatched, problems[0].Kind)
+	require.Equal(t, []string{"unmatched"}, problems[0].Configs[0].Tags)
+	require.Equal(t, "config tagged [unmatched] hasn't configured any event", problems[0].Explanation)
+}
@@ -0,0 +1,288 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tags is a set of tags to be matched by an Expression.
+type Tags interface {
+	// Has reports whether tag is contained in the set.
+	Has(tag string) bool
+	// HasPrefix reports whether a tag starting with prefix is contained
+	// in the set.
+	HasPrefix(prefix string) bool
+}
+
+// Expression is a parsed config.Selector.
+type Expression interface {
+	// Matches reports whether tags are selected by the Expression.
+	Matches(tags Tags) bool
+	// Terms returns the number of tags and prefixes the Expression
+	// refers to, as a measure of its specificity. Of alternatives, only
+	// the least specific one counts.
+	Terms() int
+}
+
+type tag string
+
+func (t tag) Matches(tags Tags) bool { return tags.Has(string(t)) }
+func (t tag) Terms() int             { return 1 }
+
+type prefix string
+
+func (p prefix) Matches(tags Tags) bool { return tags.HasPrefix(string(p)) }
+func (p prefix) Terms() int             { return 1 }
+
+type not struct{ Expression }
+
+func (n not) Matches(tags Tags) bool { return !n.Expression.Matches(tags) }
+
+type and []Expression
+
+func (a and) Matches(tags Tags) bool {
+	for _, expression := range a {
+		if !expression.Matches(tags) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a and) Terms() int { return terms(a) }
+
+type or []Expression
+
+func (o or) Matches(tags Tags) bool {
+	for _, expression := range o {
+		if expression.Matches(tags) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o or) Terms() int {
+	result := o[0].Terms()
+	for _, expression := range o[1:] {
+		result = min(result, expression.Terms())
+	}
+
+	return result
+}
+
+func terms(expressions []Expression) (result int) {
+	for _, expression := range expressions {
+		result += expression.Terms()
+	}
+
+	return result
+}
+
+// Set implements Tags for a list of tags.
+type Set []string
+
+func (s Set) Has(tag string) bool {
+	for _, t := range s {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasAll reports whether all of tags are contained in the set.
+func (s Set) HasAll(tags []string) bool {
+	for _, tag := range tags {
+		if !s.Has(tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s Set) HasPrefix(prefix string) bool {
+	for _, t := range s {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse parses selector as described for config.Selector.
+func Parse(selector string) (Expression, error) {
+	p := &parser{tokens: tokenize(selector)}
+
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+
+	if token, ok := p.peek(); ok {
+		return nil, fmt.Errorf("invalid selector %q: unexpected %q", selector, token)
+	}
+
+	return expression, nil
+}
+
+// MustParse is like Parse but panics if selector can't be parsed. It
+// returns nil for an empty selector, which selects all tags.
+func MustParse(selector string) Expression {
+	if selector == "" {
+		return nil
+	}
+
+	expression, err := Parse(selector)
+	if err != nil {
+		panic(err)
+	}
+
+	return expression
+}
+
+const operators = "!&|()"
+
+func tokenize(selector string) (tokens []string) {
+	for i := 0; i < len(selector); {
+		switch c := selector[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case strings.HasPrefix(selector[i:], "&&"), strings.HasPrefix(selector[i:], "||"), strings.HasPrefix(selector[i:], "!="):
+			tokens = append(tokens, selector[i:i+2])
+			i += 2
+		case strings.IndexByte(operators, c) >= 0:
+			tokens = append(tokens, selector[i:i+1])
+			i++
+		default:
+			end := i
+			for end < len(selector) && !strings.ContainsRune(" \t\n"+operators, rune(selector[end])) {
+				end++
+			}
+			tokens = append(tokens, selector[i:end])
+			i = end
+		}
+	}
+
+	return tokens
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (string, bool) {
+	token, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+
+	return token, ok
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	return p.parseList("||", p.parseAnd, func(expressions []Expression) Expression { return or(expressions) })
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	return p.parseList("&&", p.parseUnary, func(expressions []Expression) Expression { return and(expressions) })
+}
+
+func (p *parser) parseList(operator string, parseOperand func() (Expression, error), combine func([]Expression) Expression) (Expression, error) {
+	var expressions []Expression
+
+	for {
+		expression, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, expression)
+
+		if token, ok := p.peek(); !ok || token != operator {
+			break
+		}
+		p.next()
+	}
+
+	if len(expressions) == 1 {
+		return expressions[0], nil
+	}
+
+	return combine(expressions), nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	token, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end")
+	}
+
+	switch token {
+	case "!":
+		expression, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return not{expression}, nil
+
+	case "(":
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if token, ok := p.next(); !ok || token != ")" {
+			return nil, fmt.Errorf("missing %q", ")")
+		}
+
+		return expression, nil
+	}
+
+	if !isWord(token) {
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+
+	// A label like key!=value selects events without the tag key=value.
+	if next, ok := p.peek(); ok && next == "!=" {
+		p.next()
+
+		value, ok := p.next()
+		if !ok || !isWord(value) {
+			return nil, fmt.Errorf("missing value for %q", token)
+		}
+
+		return not{term(token + "=" + value)}, nil
+	}
+
+	return term(token), nil
+}
+
+// isWord reports whether token is a tag rather than an operator.
+func isWord(token string) bool {
+	return strings.IndexByte(operators, token[0]) < 0
+}
+
+// term returns a prefix if token ends with a wildcard, and a tag
+// otherwise.
+func term(token string) Expression {
+	if strings.HasSuffix(token, "*") {
+		return prefix(strings.TrimSuffix(token, "*"))
+	}
+
+	return tag(token)
+}
@@ -0,0 +1,158 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name      string
+		selector  string
+		tags      []string
+		wantMatch bool
+		wantTerms int
+	}{
+		{
+			name:      "tag",
+			selector:  "a",
+			tags:      []string{"a", "b"},
+			wantMatch: true,
+			wantTerms: 1,
+		},
+		{
+			name:      "missing tag",
+			selector:  "c",
+			tags:      []string{"a", "b"},
+			wantMatch: false,
+			wantTerms: 1,
+		},
+		{
+			name:      "and not",
+			selector:  "a && !b",
+			tags:      []string{"a", "b"},
+			wantMatch: false,
+			wantTerms: 2,
+		},
+		{
+			name:      "or",
+			selector:  "c || b",
+			tags:      []string{"a", "b"},
+			wantMatch: true,
+			wantTerms: 1,
+		},
+		{
+			name:      "precedence",
+			selector:  "c && a || b",
+			tags:      []string{"a", "b"},
+			wantMatch: true,
+			wantTerms: 1,
+		},
+		{
+			name:      "parentheses",
+			selector:  "c && (a || b)",
+			tags:      []string{"a", "b"},
+			wantMatch: false,
+			wantTerms: 2,
+		},
+		{
+			name:      "prefix",
+			selector:  "job:*",
+			tags:      []string{"job:import", "tenant=a"},
+			wantMatch: true,
+			wantTerms: 1,
+		},
+		{
+			name:      "missing prefix",
+			selector:  "job:*",
+			tags:      []string{"jobs", "tenant=a"},
+			wantMatch: false,
+			wantTerms: 1,
+		},
+		{
+			name:      "label",
+			selector:  "tenant=a",
+			tags:      []string{"job:import", "tenant=a"},
+			wantMatch: true,
+			wantTerms: 1,
+		},
+		{
+			name:      "negated label",
+			selector:  "tenant!=a",
+			tags:      []string{"job:import", "tenant=a"},
+			wantMatch: false,
+			wantTerms: 1,
+		},
+		{
+			name:      "negated label, missing",
+			selector:  "tenant != a",
+			tags:      []string{"job:import"},
+			wantMatch: true,
+			wantTerms: 1,
+		},
+		{
+			name:      "any label value",
+			selector:  "tenant=* && !(shard=1 || shard=2)",
+			tags:      []string{"tenant=a", "shard=3"},
+			wantMatch: true,
+			wantTerms: 2,
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := Parse(tt.selector)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantMatch, expression.Matches(Set(tt.tags)))
+			require.Equal(t, tt.wantTerms, expression.Terms())
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		selector string
+	}{
+		{name: "empty", selector: ""},
+		{name: "single ampersand", selector: "a & b"},
+		{name: "missing operand", selector: "a &&"},
+		{name: "leading operator", selector: "|| a"},
+		{name: "missing closing parenthesis", selector: "(a || b"},
+		{name: "unexpected closing parenthesis", selector: "a)"},
+		{name: "missing label value", selector: "tenant!="},
+		{name: "missing operator", selector: "a b"},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.selector)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, MustParse(""))
+	require.NotNil(t, MustParse("a"))
+	require.Panics(t, func() { MustParse("a &&") })
+}
+
+func TestSet_HasAll(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Set{"a", "b"}.HasAll([]string{"b"}))
+	require.True(t, Set{"a", "b"}.HasAll(nil))
+	require.False(t, Set{"a", "b"}.HasAll([]string{"a", "c"}))
+}
@@ -1,49 +1,53 @@
 package waitgroups
 
 import (
-	"fmt"
 	"github.com/metamogul/timestone/v2/simulation/config"
 	configinternal "github.com/metamogul/timestone/v2/simulation/internal/config"
 	"github.com/metamogul/timestone/v2/simulation/internal/data"
+	"slices"
 	"sync"
 	"time"
 )
 
 type EventWaitGroups struct {
-	waitGroups *data.TaggedStore[map[int64]*sync.WaitGroup]
+	waitGroups *data.TaggedStore[map[int64]*EventWaitGroup]
 
 	mu sync.RWMutex
 }
 
 func NewEventWaitGroups() *EventWaitGroups {
 	return &EventWaitGroups{
-		waitGroups: data.NewTaggedStore[map[int64]*sync.WaitGroup](),
+		waitGroups: data.NewTaggedStore[map[int64]*EventWaitGroup](),
 	}
 }
 
-func (e *EventWaitGroups) New(time time.Time, tags []string) *sync.WaitGroup {
+func (e *EventWaitGroups) New(time time.Time, tags []string) *EventWaitGroup {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	waitGroupsForTags := e.waitGroups.Matching(tags)
 	if waitGroupsForTags == nil {
-		waitGroupsForTags = make(map[int64]*sync.WaitGroup)
+		waitGroupsForTags = make(map[int64]*EventWaitGroup)
 		e.waitGroups.Set(waitGroupsForTags, tags)
 	}
 
 	timeUnixMilli := time.UnixMilli()
 	waitGroupForTagsAndTime, exists := waitGroupsForTags[timeUnixMilli]
 	if !exists {
-		waitGroupForTagsAndTime = new(sync.WaitGroup)
+		waitGroupForTagsAndTime = &EventWaitGroup{tags: tags, time: time}
 		waitGroupsForTags[timeUnixMilli] = waitGroupForTagsAndTime
 	}
 
-	waitGroupForTagsAndTime.Add(1)
+	waitGroupForTagsAndTime.add()
 
 	return waitGroupForTagsAndTime
 }
 
-func (e *EventWaitGroups) WaitFor(events []config.Event) {
+// WaitFor blocks until all events matching events have completed, or
+// stop is closed, and returns the latest time one of them has completed
+// at. It also returns the events that no matching event exists for,
+// which it doesn't wait for.
+func (e *EventWaitGroups) WaitFor(events []configinternal.Target, stop <-chan struct{}) (completedAt time.Time, missing []configinternal.Target) {
 	// To understand why this implementation has been chosen,
 	// consider an action with tag "action2" adding more actions tagged
 	// "action2.1", with an "action1" previously called that has been
@@ -53,48 +57,62 @@ func (e *EventWaitGroups) WaitFor(events []config.Event) {
 	// in consequence also no WaitGroup for this name. Therefore we first
 	// Wait for "action2" (or all other actions that already have a
 	// corresponding WaitGroup) to give it a chance to spawn
-	// the missing GeneratorWaitGroups and avoid a panic.
+	// the missing GeneratorWaitGroups before giving up on them.
 
 	for len(events) > 0 {
-		var remainingEvents []config.Event
+		var remainingEvents []configinternal.Target
 
 		e.mu.RLock()
 		for _, eventKey := range events {
-			foundAllWaitGroups := e.waitFor(eventKey)
+			foundAllWaitGroups, eventCompletedAt := e.waitFor(eventKey, stop)
 			if !foundAllWaitGroups {
 				remainingEvents = append(remainingEvents, eventKey)
 			}
+
+			if eventCompletedAt.After(completedAt) {
+				completedAt = eventCompletedAt
+			}
 		}
 		e.mu.RUnlock()
 
 		if len(remainingEvents) == len(events) {
-			panic(fmt.Sprintf("Wait group(s) for %v do not exist", events))
+			return completedAt, remainingEvents
 		}
 
 		events = remainingEvents
 	}
+
+	return completedAt, nil
 }
 
-func (e *EventWaitGroups) waitFor(event config.Event) (success bool) {
-	waitGroupSetsForTagsByTime := e.waitGroups.Containing(event.GetTags())
+func (e *EventWaitGroups) waitFor(event configinternal.Target, stop <-chan struct{}) (success bool, completedAt time.Time) {
+	waitGroupSetsForTagsByTime := e.waitGroups.Selecting(event.GetTags(), event.Expression)
 	if len(waitGroupSetsForTagsByTime) == 0 {
-		_, ignoreMissmatch := event.(configinternal.At)
-		return ignoreMissmatch
+		_, ignoreMissmatch := event.Event.(configinternal.At)
+		return ignoreMissmatch, completedAt
+	}
+
+	wait := func(wg *EventWaitGroup) {
+		e.mu.RUnlock() // Unlock before waiting to avoid deadlocks
+		wg.waitOrStop(stop)
+		e.mu.RLock() // Reacquire the lock after waiting
+
+		if wgCompletedAt := wg.CompletedAt(); wgCompletedAt.After(completedAt) {
+			completedAt = wgCompletedAt
+		}
 	}
 
-	switch event := event.(type) {
+	switch event := event.Event.(type) {
 
 	case configinternal.At:
 		// Wait for events at time, ignore missing match
 		for _, waitGroupsForTagsByTime := range waitGroupSetsForTagsByTime {
 			wg, exists := waitGroupsForTagsByTime[event.Time.UnixMilli()]
 			if !exists {
-				return true
+				return true, completedAt
 			}
 
-			e.mu.RUnlock() // Unlock before waiting to avoid deadlocks
-			wg.Wait()
-			e.mu.RLock() // Reacquire the lock after waiting
+			wait(wg)
 		}
 
 	case config.At:
@@ -102,36 +120,160 @@ func (e *EventWaitGroups) waitFor(event config.Event) (success bool) {
 		for _, waitGroupsForTagsByTime := range waitGroupSetsForTagsByTime {
 			wg, exists := waitGroupsForTagsByTime[event.Time.UnixMilli()]
 			if !exists {
-				return false
+				return false, completedAt
 			}
 
-			e.mu.RUnlock() // Unlock before waiting to avoid deadlocks
-			wg.Wait()
-			e.mu.RLock() // Reacquire the lock after waiting
+			wait(wg)
 		}
 
 	case config.All:
 		// Wait for all events containing tags
 		for _, waitGroupsForTagsByTime := range waitGroupSetsForTagsByTime {
-			e.mu.RUnlock() // Unlock before waiting to avoid deadlocks
 			for _, wg := range waitGroupsForTagsByTime {
-				wg.Wait()
+				wait(wg)
 			}
-			e.mu.RLock() // Reacquire the lock after waiting
 		}
 	}
 
-	return true
+	return true, completedAt
 }
 
-func (e *EventWaitGroups) Wait() {
+// Wait blocks until all events have completed, or stop is closed.
+func (e *EventWaitGroups) Wait(stop <-chan struct{}) {
 	e.mu.RLock()
 	allWaitGroupsByTime := e.waitGroups.All()
 	e.mu.RUnlock()
 
 	for _, waitGroupsByTime := range allWaitGroupsByTime {
 		for _, wg := range waitGroupsByTime {
-			wg.Wait()
+			wg.waitOrStop(stop)
 		}
 	}
 }
+
+// EventWaitGroup is a sync.WaitGroup for the events with the same tags
+// at the same time, that keeps track of when they have completed.
+type EventWaitGroup struct {
+	stoppableWaitGroup
+
+	tags []string
+	time time.Time
+
+	pending     int
+	completedAt time.Time
+	mu          sync.Mutex
+}
+
+func (w *EventWaitGroup) add() {
+	w.mu.Lock()
+	w.pending++
+	w.mu.Unlock()
+
+	w.Add(1)
+}
+
+// Complete marks one of the events as completed at completedAt, which
+// might lie in the future if the event is known to take time.
+func (w *EventWaitGroup) Complete(completedAt time.Time) {
+	w.mu.Lock()
+	if completedAt.After(w.completedAt) {
+		w.completedAt = completedAt
+	}
+	w.pending--
+	w.mu.Unlock()
+
+	w.Done()
+}
+
+// CompletedAt returns the latest time one of the events has completed
+// at.
+func (w *EventWaitGroup) CompletedAt() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.completedAt
+}
+
+// EventState is the state of the events with the same tags at the same
+// time, as returned by EventWaitGroups.States.
+type EventState struct {
+	Tags []string
+	Time time.Time
+	// Pending is the number of events that haven't completed yet.
+	Pending int
+	// CompletedAt is the latest time one of the events has completed at.
+	CompletedAt time.Time
+}
+
+// States returns the state of the events event refers to, without
+// waiting for them.
+func (e *EventWaitGroups) States(event configinternal.Target) []EventState {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var result []EventState
+	for _, wg := range e.selecting(event) {
+		wg.mu.Lock()
+		result = append(result, EventState{Tags: wg.tags, Time: wg.time, Pending: wg.pending, CompletedAt: wg.completedAt})
+		wg.mu.Unlock()
+	}
+
+	slices.SortFunc(result, func(a, b EventState) int {
+		if c := a.Time.Compare(b.Time); c != 0 {
+			return c
+		}
+
+		return slices.Compare(a.Tags, b.Tags)
+	})
+
+	return result
+}
+
+// Satisfied reports whether WaitFor would return for event right away,
+// because all events it refers to have completed.
+func (e *EventWaitGroups) Satisfied(event configinternal.Target) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	waitGroups := e.selecting(event)
+	if len(waitGroups) == 0 {
+		_, ignoreMissmatch := event.Event.(configinternal.At)
+		return ignoreMissmatch
+	}
+
+	for _, wg := range waitGroups {
+		wg.mu.Lock()
+		pending := wg.pending
+		wg.mu.Unlock()
+
+		if pending > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// selecting returns the wait groups of the events event refers to.
+func (e *EventWaitGroups) selecting(event configinternal.Target) []*EventWaitGroup {
+	var result []*EventWaitGroup
+
+	for _, waitGroupsByTime := range e.waitGroups.Selecting(event.GetTags(), event.Expression) {
+		switch event := event.Event.(type) {
+		case config.At:
+			if wg, exists := waitGroupsByTime[event.Time.UnixMilli()]; exists {
+				result = append(result, wg)
+			}
+		case configinternal.At:
+			if wg, exists := waitGroupsByTime[event.Time.UnixMilli()]; exists {
+				result = append(result, wg)
+			}
+		default:
+			for _, wg := range waitGroupsByTime {
+				result = append(result, wg)
+			}
+		}
+	}
+
+	return result
+}
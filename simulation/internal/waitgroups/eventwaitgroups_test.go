@@ -54,7 +54,7 @@ func TestEventWaitGroups_New(t *testing.T) {
 			_ = e.New(time.Time{}.Add(time.Second), []string{"testExists"})
 
 			wg := e.New(tt.time, tt.tags)
-			go func() { wg.Add(tt.addCount) }()
+			go wg.Add(tt.addCount)
 			wg.Wait()
 		})
 	}
@@ -77,7 +77,7 @@ func TestEventWaitGroups_WaitFor(t *testing.T) {
 	}()
 
 	e.WaitFor(
-		[]config.Event{
+		targets(
 			config.At{
 				Time: presentTime.Add(time.Second),
 				Tags: presentTags,
@@ -86,11 +86,51 @@ func TestEventWaitGroups_WaitFor(t *testing.T) {
 				Time: presentTime,
 				Tags: presentTags,
 			},
-		},
+		),
+		nil,
 	)
 
 }
 
+func TestEventWaitGroups_WaitFor_completedAt(t *testing.T) {
+	t.Parallel()
+
+	presentTags := []string{"test"}
+	presentTime := time.Time{}
+
+	e := NewEventWaitGroups()
+
+	wg1 := e.New(presentTime, presentTags)
+	wg2 := e.New(presentTime.Add(time.Second), presentTags)
+	go func() {
+		wg1.Complete(presentTime.Add(time.Minute))
+		wg2.Complete(presentTime.Add(time.Second))
+	}()
+
+	completedAt, missing := e.WaitFor(targets(config.All{Tags: presentTags}), nil)
+	require.Equal(t, presentTime.Add(time.Minute), completedAt)
+	require.Empty(t, missing)
+}
+
+func TestEventWaitGroups_WaitFor_missing(t *testing.T) {
+	t.Parallel()
+
+	presentTags := []string{"test"}
+	presentTime := time.Time{}
+
+	e := NewEventWaitGroups()
+
+	wg := e.New(presentTime, presentTags)
+	go wg.Complete(presentTime)
+
+	missingEvents := targets(
+		config.All{Tags: []string{"missing"}},
+		config.At{Time: presentTime.Add(time.Second), Tags: presentTags},
+	)
+	_, missing := e.WaitFor(append(targets(config.All{Tags: presentTags}), missingEvents...), nil)
+	require.Equal(t, missingEvents, missing)
+}
+
 func TestEventWaitGroups_waitFor(t *testing.T) {
 	t.Parallel()
 
@@ -155,6 +195,27 @@ func TestEventWaitGroups_waitFor(t *testing.T) {
 			waitForEventKey: config.All{Tags: []string{"test"}},
 			wantSuccess:     true,
 		},
+		{
+			name:            "match all, selector has match",
+			presentTags:     []string{"job:import", "tenant=a"},
+			presentTime:     time.Time{},
+			waitForEventKey: config.All{Selector: "job:* && tenant!=b"},
+			wantSuccess:     true,
+		},
+		{
+			name:            "match all, selector has no match",
+			presentTags:     []string{"job:import", "tenant=a"},
+			presentTime:     time.Time{},
+			waitForEventKey: config.All{Tags: []string{"job:import"}, Selector: "!tenant=a"},
+			wantSuccess:     false,
+		},
+		{
+			name:            "match time, selector has match",
+			presentTags:     []string{"job:import", "tenant=a"},
+			presentTime:     time.Time{},
+			waitForEventKey: config.At{Time: time.Time{}, Selector: "tenant=a || tenant=b"},
+			wantSuccess:     true,
+		},
 	}
 
 	for _, tt := range testcases {
@@ -169,7 +230,7 @@ func TestEventWaitGroups_waitFor(t *testing.T) {
 			}
 
 			e.mu.RLock()
-			success := e.waitFor(tt.waitForEventKey)
+			success, _ := e.waitFor(targets(tt.waitForEventKey)[0], nil)
 			e.mu.RUnlock()
 
 			require.Equal(t, tt.wantSuccess, success)
@@ -177,6 +238,45 @@ func TestEventWaitGroups_waitFor(t *testing.T) {
 	}
 }
 
+func TestEventWaitGroups_States(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	e := NewEventWaitGroups()
+	e.New(now, []string{"test1"}).Complete(now.Add(time.Second))
+	e.New(now, []string{"test1"})
+	e.New(now.Add(time.Minute), []string{"test1", "test2"})
+	e.New(now, []string{"test3"})
+
+	require.Equal(t,
+		[]EventState{
+			{Tags: []string{"test1"}, Time: now, Pending: 1, CompletedAt: now.Add(time.Second)},
+			{Tags: []string{"test1", "test2"}, Time: now.Add(time.Minute), Pending: 1},
+		},
+		e.States(targets(config.All{Tags: []string{"test1"}})[0]),
+	)
+	require.Len(t, e.States(targets(config.At{Time: now, Tags: []string{"test1"}})[0]), 1)
+}
+
+func TestEventWaitGroups_Satisfied(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	e := NewEventWaitGroups()
+	e.New(now, []string{"test1"}).Complete(now)
+	e.New(now.Add(time.Minute), []string{"test2"})
+
+	satisfied := func(event config.Event) bool { return e.Satisfied(targets(event)[0]) }
+
+	require.True(t, satisfied(config.All{Tags: []string{"test1"}}))
+	require.False(t, satisfied(config.All{Tags: []string{"test2"}}))
+	require.False(t, satisfied(config.All{Tags: []string{"test3"}}))
+	require.False(t, satisfied(config.At{Time: now, Tags: []string{"test2"}}))
+	require.True(t, satisfied(configinternal.At{Time: now, Tags: []string{"test2"}}))
+}
+
 func TestEvenWaitGroups_Wait(t *testing.T) {
 	t.Parallel()
 
@@ -189,5 +289,20 @@ func TestEvenWaitGroups_Wait(t *testing.T) {
 		go func() { wg1.Done(); wg2.Done() }()
 	}
 
-	e.Wait()
+	e.Wait(nil)
+}
+
+// targets returns events as configinternal.Target s, with their
+// selectors parsed.
+func targets(events ...config.Event) []configinternal.Target {
+	result := make([]configinternal.Target, len(events))
+	for i, event := range events {
+		target, err := configinternal.ParseTarget(event)
+		if err != nil {
+			panic(err)
+		}
+		result[i] = target
+	}
+
+	return result
 }
@@ -24,7 +24,7 @@ func (w *GeneratorWaitGroups) Add(delta int, tags []string) {
 
 	matchingEntry := w.waitGroups.Matching(tags)
 	if matchingEntry == nil {
-		matchingEntry = &waitGroup{}
+		matchingEntry = &waitGroup{tags: tags}
 		w.waitGroups.Set(matchingEntry, tags)
 	}
 
@@ -45,7 +45,9 @@ func (w *GeneratorWaitGroups) Done(tags []string) {
 	}
 }
 
-func (w *GeneratorWaitGroups) WaitFor(tags []string) {
+// WaitFor blocks until the generators expected with tags have been
+// added, or stop is closed.
+func (w *GeneratorWaitGroups) WaitFor(tags []string, stop <-chan struct{}) {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
@@ -54,5 +56,31 @@ func (w *GeneratorWaitGroups) WaitFor(tags []string) {
 		panic(fmt.Sprintf("WaitGroup for %v does not exist", tags))
 	}
 
-	waitGroupForTags.wait()
+	waitGroupForTags.wait(stop)
+}
+
+// Expectation is a number of generators with tags that are still
+// expected to be added.
+type Expectation struct {
+	Tags  []string
+	Count int
+}
+
+// Pending returns the generators that are expected, but haven't been
+// added yet. Unlike the other methods, it doesn't block if the
+// GeneratorWaitGroups are being modified, but returns false.
+func (w *GeneratorWaitGroups) Pending() ([]Expectation, bool) {
+	if !w.mu.TryRLock() {
+		return nil, false
+	}
+	defer w.mu.RUnlock()
+
+	var result []Expectation
+	for _, waitGroup := range w.waitGroups.All() {
+		if count := waitGroup.pending(); count > 0 {
+			result = append(result, Expectation{Tags: waitGroup.tags, Count: count})
+		}
+	}
+
+	return result, true
 }
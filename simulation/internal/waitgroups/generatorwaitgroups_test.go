@@ -22,7 +22,7 @@ func Test_GeneratorWaitGroups_Add(t *testing.T) {
 
 	w.Add(1, []string{"test1"})
 	go func() { w.Done([]string{"test1", "test2"}) }()
-	w.WaitFor([]string{"test1"})
+	w.WaitFor([]string{"test1"}, nil)
 }
 
 func Test_GeneratorWaitGroups_Done(t *testing.T) {
@@ -35,7 +35,7 @@ func Test_GeneratorWaitGroups_Done(t *testing.T) {
 		go func() {
 			w.Done([]string{"testGroup", "test1"})
 		}()
-		w.WaitFor([]string{"testGroup", "test1"})
+		w.WaitFor([]string{"testGroup", "test1"}, nil)
 	})
 
 	t.Run("sufficient done calls", func(t *testing.T) {
@@ -44,7 +44,7 @@ func Test_GeneratorWaitGroups_Done(t *testing.T) {
 			w.Done([]string{"testGroup", "test1"})
 			w.Done([]string{"testGroup", "test2"})
 		}()
-		w.WaitFor([]string{"testGroup"})
+		w.WaitFor([]string{"testGroup"}, nil)
 	})
 
 	t.Run("more than sufficient done calls", func(t *testing.T) {
@@ -54,7 +54,7 @@ func Test_GeneratorWaitGroups_Done(t *testing.T) {
 			w.Done([]string{"testGroup", "test2"})
 			w.Done([]string{"testGroup", "test3"})
 		}()
-		w.WaitFor([]string{"testGroup"})
+		w.WaitFor([]string{"testGroup"}, nil)
 	})
 
 }
@@ -77,14 +77,28 @@ func Test_GeneratorWaitGroups_WaitFor(t *testing.T) {
 			w.Done([]string{"test1", "test2"})
 			w.Done([]string{"test3", "test4"})
 		}()
-		w.WaitFor([]string{"test1", "test2"})
-		w.WaitFor([]string{"test3", "test4"})
+		w.WaitFor([]string{"test1", "test2"}, nil)
+		w.WaitFor([]string{"test3", "test4"}, nil)
 	})
 
 	t.Run("wait group for tags doesn't exist", func(t *testing.T) {
 		t.Parallel()
 
 		w := NewGeneratorWaitGroups()
-		require.Panics(t, func() { w.WaitFor([]string{"test5", "test2"}) })
+		require.Panics(t, func() { w.WaitFor([]string{"test5", "test2"}, nil) })
 	})
 }
+
+func Test_GeneratorWaitGroups_Pending(t *testing.T) {
+	t.Parallel()
+
+	w := NewGeneratorWaitGroups()
+	w.Add(2, []string{"test1"})
+	w.Add(1, []string{"test2"})
+	w.Done([]string{"test1"})
+	w.Done([]string{"test2"})
+
+	pending, ok := w.Pending()
+	require.True(t, ok)
+	require.Equal(t, []Expectation{{Tags: []string{"test1"}, Count: 1}}, pending)
+}
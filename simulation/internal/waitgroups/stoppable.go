@@ -0,0 +1,63 @@
+package waitgroups
+
+import "sync"
+
+// stoppableWaitGroup is a sync.WaitGroup that can also be waited for
+// until a channel is closed, so that a waiting goroutine isn't stuck if
+// the counter never drops to zero.
+type stoppableWaitGroup struct {
+	count int
+	// idle is closed once count has dropped to zero.
+	idle chan struct{}
+	mu   sync.Mutex
+}
+
+// Add adds delta to the counter like sync.WaitGroup.Add.
+func (w *stoppableWaitGroup) Add(delta int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.count+delta < 0 {
+		panic("waitgroups: negative counter")
+	}
+
+	if w.count == 0 && delta > 0 {
+		w.idle = make(chan struct{})
+	}
+
+	w.count += delta
+	if w.count == 0 && delta < 0 {
+		close(w.idle)
+	}
+}
+
+// Done decrements the counter by one.
+func (w *stoppableWaitGroup) Done() {
+	w.Add(-1)
+}
+
+// Wait blocks until the counter is zero.
+func (w *stoppableWaitGroup) Wait() {
+	w.waitOrStop(nil)
+}
+
+// waitOrStop blocks until the counter is zero or stop is closed, and
+// reports whether the counter has dropped to zero. A nil stop is never
+// closed.
+func (w *stoppableWaitGroup) waitOrStop(stop <-chan struct{}) bool {
+	w.mu.Lock()
+	idle := w.idle
+	count := w.count
+	w.mu.Unlock()
+
+	if count == 0 {
+		return true
+	}
+
+	select {
+	case <-idle:
+		return true
+	case <-stop:
+		return false
+	}
+}
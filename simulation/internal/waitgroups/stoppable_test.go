@@ -0,0 +1,31 @@
+package waitgroups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_stoppableWaitGroup_waitOrStop(t *testing.T) {
+	t.Parallel()
+
+	w := stoppableWaitGroup{}
+	require.True(t, w.waitOrStop(nil))
+
+	w.Add(2)
+	stop := make(chan struct{})
+	close(stop)
+	require.False(t, w.waitOrStop(stop))
+
+	go func() {
+		w.Done()
+		w.Done()
+	}()
+	require.True(t, w.waitOrStop(nil))
+
+	w.Add(1)
+	go w.Done()
+	w.Wait()
+
+	require.Panics(t, func() { w.Add(-1) })
+}
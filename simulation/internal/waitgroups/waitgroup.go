@@ -3,7 +3,8 @@ package waitgroups
 import "sync"
 
 type waitGroup struct {
-	waitGroup sync.WaitGroup
+	waitGroup stoppableWaitGroup
+	tags      []string
 	count     int
 	mu        sync.Mutex
 }
@@ -36,6 +37,14 @@ func (w *waitGroup) done() {
 	w.waitGroup.Done()
 }
 
-func (w *waitGroup) wait() {
-	w.waitGroup.Wait()
+// wait blocks until the counter is zero or stop is closed.
+func (w *waitGroup) wait(stop <-chan struct{}) {
+	w.waitGroup.waitOrStop(stop)
+}
+
+func (w *waitGroup) pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.count
 }
@@ -55,7 +55,7 @@ func Test_waitGroup_add(t *testing.T) {
 					w.done()
 				}()
 			}
-			w.wait()
+			w.wait(nil)
 		})
 	}
 }
@@ -95,7 +95,7 @@ func Test_waitGroup_done(t *testing.T) {
 			for range tt.timesDone {
 				go func() { w.done() }()
 			}
-			w.wait()
+			w.wait(nil)
 		})
 	}
 }
@@ -112,5 +112,5 @@ func Test_waitGroup_wait(t *testing.T) {
 		go func() { w.done() }()
 	}
 
-	w.wait()
+	w.wait(nil)
 }
@@ -0,0 +1,131 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+)
+
+// Option configures a Scheduler created by NewScheduler.
+type Option func(s *Scheduler)
+
+// RunUntilIdle lets the run loop track the actions in flight, and wait
+// after executing an Event until all of them are idle before executing
+// the next one. An action is idle once it has finished or has been
+// parked by timestone.Sleep or timestone.After. Event generators added
+// through the context.Context passed to an action are known to the run
+// loop once it is idle, so that config.Config.Adds doesn't need to be
+// declared for the run loop to take them into account.
+//
+// An action blocking on anything else that only a following Event
+// provides, like the channel of a timestone.Timer, the context.Context
+// returned by timestone.WithTimeout or another action, keeps the run
+// loop waiting, as reported by Watchdog. Wait for those via
+// timestone.After or timestone.Sleep instead, e.g. by sleeping with the
+// context.Context returned by timestone.WithTimeout.
+func RunUntilIdle() Option {
+	return func(s *Scheduler) {
+		s.inflight = &inflight{}
+	}
+}
+
+// StrictConfigs makes the Scheduler report when an Event matches
+// multiple config.Config s passed to ConfigureEvents that are equally
+// specific, as a config.ProblemAmbiguous returned by ValidateConfigs once
+// it has been forwarded. The one configured last is applied nonetheless.
+func StrictConfigs() Option {
+	return func(s *Scheduler) {
+		s.eventConfigs.SetStrict(true)
+	}
+}
+
+// TieBreak determines the order of simultaneous Event s with the same
+// config.Config.Priority.
+type TieBreak = events.TieBreak
+
+const (
+	// TieBreakFIFO orders Event s by the order their generators have been
+	// queued in, which is the default. It is stable, but hides ordering
+	// bugs that depend on that order.
+	TieBreakFIFO = events.TieBreakFIFO
+	// TieBreakLIFO orders the Event s of generators added last first.
+	TieBreakLIFO = events.TieBreakLIFO
+	// TieBreakRandom orders Event s randomly, as determined by Seed. It
+	// also perturbs the start order of Event s released concurrently,
+	// which increases the chance of surfacing ordering bugs, but unlike
+	// the order of the event queue can't be reproduced exactly.
+	TieBreakRandom = events.TieBreakRandom
+	// TieBreakLexical orders Event s lexically by their tags.
+	TieBreakLexical = events.TieBreakLexical
+)
+
+// TieBreakBy makes the Scheduler order simultaneous Event s with the
+// same config.Config.Priority by tieBreak instead of TieBreakFIFO.
+func TieBreakBy(tieBreak TieBreak) Option {
+	return func(s *Scheduler) {
+		s.tieBreak = tieBreak
+	}
+}
+
+// Seed sets the seed of the random decisions taken by the Scheduler,
+// which is 0 by default. Running a test for many seeds with
+// TieBreakRandom, e.g. in a subtest per seed, reveals the seed for which
+// it fails.
+func Seed(seed uint64) Option {
+	return func(s *Scheduler) {
+		s.seed = seed
+	}
+}
+
+// Decisions makes the Scheduler draw its decisions from data, so that a
+// fuzz target can drive the order of Event s, e.g.
+//
+//	f.Fuzz(func(t *testing.T, data []byte) {
+//		s := simulation.NewScheduler(now, simulation.Decisions(data))
+//		// ...
+//	})
+//
+// Of simultaneous Event s that could be executed in different orders, as
+// explored by Explore, a byte picks the one to execute next. For every
+// Event released, a byte decides whether the run loop waits for it to be
+// idle, as with RunUntilIdle, before executing the next one, or lets it
+// run concurrently. Once data has been exhausted, the Scheduler executes
+// the Event whose tags sort first and waits for it.
+//
+// A corpus entry reproduces the order in which the run loop executes
+// Event s, that is the choices between simultaneous ones, but not how
+// the actions of Event s it lets run concurrently interleave with later
+// ones, which is left to the Go runtime. A corpus entry replays the same
+// Trace only if the run loop waits for every Event it decides on.
+func Decisions(data []byte) Option {
+	return func(s *Scheduler) {
+		s.decisions = &decisions{data: data}
+	}
+}
+
+// RecordTrace makes the Scheduler record a Trace of the Event s it
+// executes, as returned by Scheduler.Trace.
+func RecordTrace() Option {
+	return func(s *Scheduler) {
+		s.trace = &Trace{}
+	}
+}
+
+// Watchdog makes Scheduler.Forward, Scheduler.ForwardOne, Scheduler.Wait
+// and Scheduler.WaitFor fail t once the run loop has been blocked for
+// longer than budget in real time, e.g. by an Event waiting for one that
+// never completes, or by config.Config.Adds expecting a generator that is
+// never added. The failure reports the Event s that are blocked along
+// with the config.Event s they wait for, the Event s whose actions are
+// still running, the expected generators that haven't been added, and the
+// goroutine stacks of the actions.
+//
+// As with testing.TB.Fatal, the goroutine that has called the method is
+// stopped, so it should be the one running the test. The Scheduler can't
+// be used afterwards.
+func Watchdog(t testing.TB, budget time.Duration) Option {
+	return func(s *Scheduler) {
+		s.watchdog = &watchdog{t: t, budget: budget}
+	}
+}
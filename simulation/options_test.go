@@ -0,0 +1,321 @@
+package simulation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunUntilIdle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var order []string
+	var mu sync.Mutex
+	record := func(step string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		order = append(order, step)
+	}
+
+	s := NewScheduler(now, RunUntilIdle())
+	s.ConfigureEvents(
+		config.Config{
+			Tags:     []string{"parent"},
+			Priority: 1,
+		},
+		config.Config{
+			Tags:     []string{"other"},
+			Priority: 2,
+			WaitFor:  []config.Event{config.All{Tags: []string{"child"}}},
+		},
+	)
+
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		record("parent")
+		s.PerformNow(ctx, timestone.SimpleAction(func(context.Context) {
+			record("child")
+		}), "child")
+	}), "parent")
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {
+		record("other")
+	}), "other")
+
+	s.Forward(time.Minute)
+
+	require.Equal(t, []string{"parent", "child", "other"}, order)
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestRunUntilIdle_parked(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var order []string
+	var mu sync.Mutex
+	record := func(step string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		order = append(order, step)
+	}
+
+	s := NewScheduler(now, RunUntilIdle())
+
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		record("before sleep")
+		_ = timestone.Sleep(ctx, 2*time.Minute)
+		record("after sleep")
+	}), "sleeping")
+	s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) {
+		record("other")
+	}), time.Minute, "other")
+
+	s.Forward(time.Hour)
+
+	require.Equal(t, []string{"before sleep", "other", "after sleep"}, order)
+}
+
+func TestRunUntilIdle_waiting(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name string
+		wait func(ctx context.Context, s *Scheduler)
+	}{
+		{
+			name: "after",
+			wait: func(ctx context.Context, _ *Scheduler) {
+				select {
+				case <-timestone.After(ctx, time.Minute):
+				case <-ctx.Done():
+				}
+			},
+		},
+		{
+			name: "timeout",
+			wait: func(ctx context.Context, s *Scheduler) {
+				ctx, cancel := timestone.WithTimeout(ctx, s, time.Minute, "timeout")
+				defer cancel()
+
+				_ = timestone.Sleep(ctx, time.Hour)
+			},
+		},
+		{
+			name: "goroutine started by the action",
+			wait: func(ctx context.Context, _ *Scheduler) {
+				done := make(chan struct{})
+				go func() {
+					<-timestone.After(ctx, time.Minute)
+					close(done)
+				}()
+				<-done
+			},
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var order []string
+			var mu sync.Mutex
+			record := func(step string) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				order = append(order, step)
+			}
+
+			s := NewScheduler(now, RunUntilIdle())
+
+			s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+				tt.wait(ctx, s)
+				record("woken")
+				s.PerformNow(ctx, timestone.SimpleAction(func(context.Context) {
+					record("child")
+				}), "child")
+			}), "waiting")
+			s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) {
+				record("other")
+			}), 2*time.Minute, "other")
+
+			s.Forward(time.Hour)
+
+			require.Equal(t, []string{"woken", "child", "other"}, order)
+		})
+	}
+}
+
+func TestStrictConfigs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now, StrictConfigs())
+	s.ConfigureEvents(
+		config.Config{Tags: []string{"job", "tenant-a"}, Priority: 1},
+		config.Config{Tags: []string{"job", "nightly"}, Priority: 2},
+	)
+
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "job", "tenant-a", "nightly")
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "job", "tenant-a", "nightly")
+
+	s.Forward(time.Minute)
+
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, s.ValidateConfigs(), &validationErr)
+
+	var kinds []config.ProblemKind
+	for _, problem := range validationErr.Problems {
+		kinds = append(kinds, problem.Kind)
+	}
+	require.Equal(t, []config.ProblemKind{config.ProblemUnmatched, config.ProblemAmbiguous}, kinds)
+}
+
+func TestTieBreakBy(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name    string
+		options []Option
+		want    []string
+	}{
+		{
+			name: "FIFO",
+			want: []string{"b", "c", "a"},
+		},
+		{
+			name:    "LIFO",
+			options: []Option{TieBreakBy(TieBreakLIFO)},
+			want:    []string{"a", "c", "b"},
+		},
+		{
+			name:    "lexical",
+			options: []Option{TieBreakBy(TieBreakLexical)},
+			want:    []string{"a", "b", "c"},
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, performSimultaneously(append(tt.options, RunUntilIdle())...))
+		})
+	}
+}
+
+func TestSeed(t *testing.T) {
+	t.Parallel()
+
+	orders := make(map[string]bool)
+	for seed := range uint64(20) {
+		order := performSimultaneously(RunUntilIdle(), TieBreakBy(TieBreakRandom), Seed(seed))
+		require.Equal(t, order, performSimultaneously(RunUntilIdle(), TieBreakBy(TieBreakRandom), Seed(seed)))
+		require.ElementsMatch(t, []string{"a", "b", "c"}, order)
+
+		orders[order[0]+order[1]+order[2]] = true
+	}
+
+	require.Greater(t, len(orders), 1)
+}
+
+// performSimultaneously returns the order in which a Scheduler created
+// with options performs actions tagged b, c and a scheduled in this
+// order for the same time.
+func performSimultaneously(options ...Option) []string {
+	return performTagged(NewScheduler(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), options...), []string{"b", "c", "a"})
+}
+
+func TestDecisions(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name string
+		data []byte
+		want []string
+	}{
+		{
+			name: "exhausted",
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "choices",
+			data: []byte{1, 0, 1, 0},
+			want: []string{"b", "c", "a"},
+		},
+		{
+			name: "choices wrap around",
+			data: []byte{5, 2, 3, 4},
+			want: []string{"c", "b", "a"},
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, performSimultaneously(Decisions(tt.data)))
+		})
+	}
+}
+
+func TestDecisions_trace(t *testing.T) {
+	t.Parallel()
+
+	// Even bytes make the run loop wait for every action, so that the
+	// corpus entry reproduces the trace exactly.
+	data := []byte{2, 0, 4, 0, 2, 0, 6, 0, 2, 0, 4, 0}
+
+	replay := func() []TraceEvent {
+		s := NewScheduler(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), Decisions(data), RecordTrace())
+		for _, tag := range []string{"b", "c", "a"} {
+			s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+				s.PerformNow(ctx, timestone.SimpleAction(func(context.Context) {}), tag+"-child")
+			}), tag)
+		}
+		s.Forward(time.Minute)
+
+		traceEvents := s.Trace().Events()
+		for i := range traceEvents {
+			traceEvents[i].Started, traceEvents[i].Ended = time.Time{}, time.Time{}
+		}
+
+		return traceEvents
+	}
+
+	traceEvents := replay()
+	require.Len(t, traceEvents, 6)
+	for range 10 {
+		require.Equal(t, traceEvents, replay())
+	}
+}
+
+func FuzzDecisions(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1, 0, 1, 0})
+	f.Add([]byte{2, 1, 1, 1})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		order := performSimultaneously(Decisions(data))
+		require.ElementsMatch(t, []string{"a", "b", "c"}, order)
+
+		// Choices reproduce exactly as long as the run loop waits for
+		// the first two actions, as decided by the second and fourth
+		// byte.
+		if (len(data) < 2 || data[1]&1 == 0) && (len(data) < 4 || data[3]&1 == 0) {
+			require.Equal(t, order, performSimultaneously(Decisions(data)))
+		}
+	})
+}
@@ -2,39 +2,88 @@ package simulation
 
 import (
 	"context"
+	"github.com/metamogul/timestone/v2/cron"
+	"github.com/metamogul/timestone/v2/internal"
 	"github.com/metamogul/timestone/v2/simulation/config"
 
 	"github.com/metamogul/timestone/v2/simulation/internal/clock"
+	configinternal "github.com/metamogul/timestone/v2/simulation/internal/config"
 	"github.com/metamogul/timestone/v2/simulation/internal/events"
 	"github.com/metamogul/timestone/v2/simulation/internal/waitgroups"
+	"math/rand/v2"
+	"runtime"
 	"sync"
 	"time"
 
 	"github.com/metamogul/timestone/v2"
 )
 
+var _ timestone.HandleScheduler = (*Scheduler)(nil)
+
 type Scheduler struct {
 	clock *clock.Clock
 
 	eventQueue        *events.Queue
-	eventGeneratorsMu sync.RWMutex
+	eventGeneratorsMu sync.Mutex
 
 	eventConfigs *events.Configs
 
 	eventWaitGroups *waitgroups.EventWaitGroups
+
+	// inflight tracks the actions in flight if the Scheduler has been
+	// created with RunUntilIdle.
+	inflight *inflight
+
+	tieBreak TieBreak
+	seed     uint64
+	// rand perturbs the start order of Event s for TieBreakRandom.
+	rand *rand.Rand
+
+	decisions *decisions
+
+	trace *Trace
+
+	// pending holds the Event s whose actions haven't completed yet.
+	pending     *pendingEvents
+	watchdog    *watchdog
+	breakpoints *breakpoints
+	observers   internal.Observers
+
+	// forwarded is true once the Scheduler has executed Event s.
+	forwarded bool
 }
 
+// maxReleaseYields is the maximum number of times an Event yields the
+// processor after being released for TieBreakRandom.
+const maxReleaseYields = 8
+
 // NewScheduler will return a newMatching Scheduler instance, with its
 // clock initialized to return now.
-func NewScheduler(now time.Time) *Scheduler {
+func NewScheduler(now time.Time, options ...Option) *Scheduler {
 	eventConfigs := events.NewConfigs()
 
-	return &Scheduler{
+	s := &Scheduler{
 		clock:           clock.NewClock(now),
 		eventQueue:      events.NewQueue(eventConfigs),
 		eventConfigs:    eventConfigs,
 		eventWaitGroups: waitgroups.NewEventWaitGroups(),
+		pending:         &pendingEvents{},
+		breakpoints:     &breakpoints{},
 	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.eventQueue.SetTieBreak(s.tieBreak, s.seed)
+	if s.tieBreak == TieBreakRandom {
+		s.rand = rand.New(rand.NewPCG(s.seed, ^s.seed))
+	}
+	if s.decisions != nil {
+		s.eventQueue.SetChooser(s.decisions)
+	}
+
+	return s
 }
 
 func (s *Scheduler) Now() time.Time {
@@ -42,39 +91,111 @@ func (s *Scheduler) Now() time.Time {
 }
 
 // ConfigureEvents provides an config.Config to configure one ore multiple events.
-func (s *Scheduler) ConfigureEvents(configs ...config.Config) {
+//
+// An event is configured by the most specific config.Config it matches:
+// one with a set Time takes precedence over one without, then the one
+// with more tags wins, counting those referred to by its
+// config.Selector. If still multiple config.Config s apply, the one
+// configured last wins, unless the Scheduler has been created with
+// StrictConfigs.
+//
+// ConfigureEvents validates configs, and returns a
+// *config.ValidationError reporting their problems that can be told
+// without executing any Event, like ValidateConfigs does before the
+// Scheduler has been forwarded, including those involving config.Config
+// s configured before. Problems of only the config.Config s configured
+// before aren't reported again. A config.Config whose selectors can't be
+// parsed isn't configured, all others are configured nonetheless.
+func (s *Scheduler) ConfigureEvents(configs ...config.Config) error {
+	since := s.eventConfigs.Mark()
 	for _, configuration := range configs {
 		s.eventConfigs.Set(configuration)
 	}
+
+	if problems := s.eventConfigs.Validate(since); len(problems) > 0 {
+		return &config.ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// ValidateConfigs returns a *config.ValidationError reporting the
+// problems of the config.Config s passed to ConfigureEvents, or nil if
+// there are none. It detects config.Config.WaitFor cycles between
+// simultaneous Event s, config.At targets that can't exist when they are
+// waited for, config.Before with a positive Interval, and Event s
+// waiting for simultaneous ones with a greater config.Config.Priority.
+// Once the Scheduler has been forwarded, it also reports the
+// config.Config s that haven't configured any Event until the current
+// time of its clock, the Event s in config.Config.WaitFor that no Event
+// existed for when they were waited for, and, if the Scheduler has been
+// created with StrictConfigs, the config.Config s that have been equally
+// specific for an Event.
+func (s *Scheduler) ValidateConfigs() error {
+	problems := s.eventConfigs.Validate(events.Mark{})
+	if s.forwarded {
+		problems = append(problems, s.eventConfigs.Unmatched(s.Now())...)
+		problems = append(problems, s.eventConfigs.Missing()...)
+		problems = append(problems, s.eventConfigs.Ambiguous()...)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return &config.ValidationError{Problems: problems}
+}
+
+// Trace returns the Trace recorded by the Scheduler if it has been
+// created with RecordTrace, and nil otherwise.
+func (s *Scheduler) Trace() *Trace {
+	return s.trace
 }
 
 // ForwardOne executes just the next event that is scheduled on the
 // event queue of the Scheduler, and sets the timestone.Clock of the Scheduler
 // to the time of the event.
 func (s *Scheduler) ForwardOne() {
-	s.eventGeneratorsMu.RLock()
+	s.eventGeneratorsMu.Lock()
 
 	if s.eventQueue.Finished() {
-		s.eventGeneratorsMu.RUnlock()
+		s.eventGeneratorsMu.Unlock()
 		return
 	}
 
 	nextEvent := s.eventQueue.Pop()
-	s.eventGeneratorsMu.RUnlock()
+	s.eventGeneratorsMu.Unlock()
 
 	s.execEvent(nextEvent)
+	s.forwarded = true
 }
 
 // WaitFor is to be used after ForwardOne and blocks until all scheduled
 // events embedding actions with the specified actionNames have finished.
+// It doesn't wait for events that don't exist, nor for those whose
+// Selector can't be parsed.
 func (s *Scheduler) WaitFor(events ...config.Event) {
-	s.eventWaitGroups.WaitFor(events)
+	targets := parseTargets(events)
+	s.await("events", func(stop <-chan struct{}) { s.eventWaitGroups.WaitFor(targets, stop) })
+}
+
+// parseTargets returns the targets of the events whose Selector can be
+// parsed.
+func parseTargets(targets []config.Event) []configinternal.Target {
+	var result []configinternal.Target
+	for _, target := range targets {
+		if parsed, err := configinternal.ParseTarget(target); err == nil {
+			result = append(result, parsed)
+		}
+	}
+
+	return result
 }
 
 // Wait is to be used after ForwardOne and blocks until all scheduled
 // events have finished.
 func (s *Scheduler) Wait() {
-	s.eventWaitGroups.Wait()
+	s.await("all events", s.eventWaitGroups.Wait)
 }
 
 // Forward will forward the Scheduler.Clock while running all events to
@@ -88,7 +209,8 @@ func (s *Scheduler) Wait() {
 //
 // Event s will be materialized and executed from the schedulers event queue in
 // temporal order. In case of simultaneousness, the exection order can be changed
-// with the Config.Priority passed through ConfigureEvents.
+// with the Config.Priority passed through ConfigureEvents. Remaining ties are
+// broken as set by TieBreakBy.
 //
 // Event s configured via their Config.WaitFor will only start
 // execution once the specified events have finished.
@@ -96,32 +218,129 @@ func (s *Scheduler) Wait() {
 // Event s configured via Config.Adds will block the run
 // loop until the specified Generator instances have been passed to the
 // Scheduler, either via one of the Perform... methods or via AddEventGenerators.
+//
+// Actions parked by timestone.Sleep or timestone.After are resumed by
+// Event s with their tags, which the run loop performs sequentially until
+// the action has been parked again or has finished. The same applies to
+// actions taking time as modelled by Config.Duration, which are parked
+// until their completion, and to Event s waiting for them.
+//
+// If the Scheduler has been created with Watchdog, Forward, ForwardOne,
+// Wait and WaitFor fail the test instead of blocking forever.
 func (s *Scheduler) Forward(interval time.Duration) {
 	targetTime := s.clock.Now().Add(interval)
 
-	for s.execNextEvent(targetTime) {
+	for {
+		for s.execNextEvent(targetTime) {
+		}
+
+		if s.inflight != nil {
+			s.await("the actions to be idle", s.inflight.wait)
+		} else {
+			s.await("all events", s.eventWaitGroups.Wait)
+		}
+
+		// Actions parked by timestone.Sleep might have added events to
+		// resume them in the meantime.
+		if !s.hasEventsUntil(targetTime) {
+			s.forwarded = true
+			return
+		}
+	}
+}
+
+// ForwardTo forwards the Scheduler like Forward until the time
+// targetTime, which must not be before the current time of its clock.
+func (s *Scheduler) ForwardTo(targetTime time.Time) {
+	if targetTime.Before(s.clock.Now()) {
+		panic("target time must not be before the current time")
+	}
+
+	s.Forward(targetTime.Sub(s.clock.Now()))
+}
+
+// ForwardInstant forwards the Scheduler like Forward to the time of the
+// next Event, executing all Event s at that time, including those added
+// for the same time in the meantime. It doesn't do anything if there
+// are no Event s.
+func (s *Scheduler) ForwardInstant() {
+	nextEventTime, ok := s.NextEventTime()
+	if !ok {
+		return
+	}
+
+	s.ForwardTo(latest(nextEventTime, s.clock.Now()))
+}
+
+// ForwardN executes the next n Event s like ForwardOne, including those
+// resuming parked actions, and waits for all of them to finish like
+// Wait. It stops early if there are no Event s left.
+func (s *Scheduler) ForwardN(n int) {
+	for range n {
+		if _, ok := s.NextEventTime(); !ok {
+			break
+		}
+
+		s.ForwardOne()
+	}
+
+	s.Wait()
+}
+
+// ForwardUntil forwards the Scheduler like ForwardInstant, one time of
+// its Event s after the other, until condition is true, but at most
+// for maxDuration. condition is checked before forwarding, and after
+// all Event s at a time have finished. ForwardUntil reports whether
+// condition has become true. If it hasn't, the clock of the Scheduler
+// has been forwarded by maxDuration.
+func (s *Scheduler) ForwardUntil(condition func() bool, maxDuration time.Duration) bool {
+	deadline := s.clock.Now().Add(maxDuration)
+
+	for !condition() {
+		nextEventTime, ok := s.NextEventTime()
+		if !ok || nextEventTime.After(deadline) {
+			s.ForwardTo(deadline)
+			return condition()
+		}
+
+		s.ForwardInstant()
+	}
+
+	return true
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
 	}
 
-	s.eventWaitGroups.Wait()
+	return b
+}
+
+func (s *Scheduler) hasEventsUntil(targetTime time.Time) bool {
+	s.eventGeneratorsMu.Lock()
+	defer s.eventGeneratorsMu.Unlock()
+
+	return !s.eventQueue.Finished() && !s.eventQueue.Peek().After(targetTime)
 }
 
 func (s *Scheduler) execNextEvent(targetTime time.Time) (shouldContinue bool) {
-	s.eventGeneratorsMu.RLock()
+	s.eventGeneratorsMu.Lock()
 
 	if s.eventQueue.Finished() {
 		s.clock.Set(targetTime)
-		s.eventGeneratorsMu.RUnlock()
+		s.eventGeneratorsMu.Unlock()
 		return false
 	}
 
 	if s.eventQueue.Peek().After(targetTime) {
 		s.clock.Set(targetTime)
-		s.eventGeneratorsMu.RUnlock()
+		s.eventGeneratorsMu.Unlock()
 		return false
 	}
 
 	nextEvent := s.eventQueue.Pop()
-	s.eventGeneratorsMu.RUnlock()
+	s.eventGeneratorsMu.Unlock()
 
 	s.execEvent(nextEvent)
 
@@ -129,21 +348,93 @@ func (s *Scheduler) execNextEvent(targetTime time.Time) (shouldContinue bool) {
 }
 
 func (s *Scheduler) execEvent(eventToExec *events.Event) {
-	s.clock.Set(eventToExec.Time)
+	// An event resuming a parked action might be added only after the
+	// clock has been forwarded past it.
+	if eventToExec.Time.After(s.clock.Now()) {
+		s.clock.Set(eventToExec.Time)
+	}
 
 	blockingEvents := s.eventConfigs.BlockingEvents(eventToExec)
 	expectedGenerators := s.eventConfigs.ExpectedGenerators(eventToExec)
+	duration := s.eventConfigs.Duration(eventToExec)
 
 	s.eventQueue.ExpectGenerators(expectedGenerators)
 
+	beforeHooks, afterHooks := s.breakpoints.matching(eventToExec)
+	for _, hook := range beforeHooks {
+		hook(eventInfo(eventToExec))
+	}
+
+	var traceEvent *TraceEvent
+	if s.trace != nil {
+		traceEvent = s.trace.add(eventToExec, configinternal.Events(blockingEvents))
+	}
+	pendingEvent := s.pending.add(eventToExec, blockingEvents)
+
 	eventWaitGroup := s.eventWaitGroups.New(eventToExec.Time, eventToExec.Tags())
+	idle := make(chan struct{})
+	s.inflight.started()
+	execution := newExecution(s, eventToExec, duration, func(completedAt time.Time) {
+		s.trace.completed(traceEvent, completedAt)
+		s.pending.completed(pendingEvent)
+		eventWaitGroup.Complete(completedAt)
+		s.inflight.stopped()
+		close(idle)
+	})
+	execution.traceEvent = traceEvent
+	releaseYields := s.releaseYields()
+	performed := make(chan struct{})
 	go func() {
-		s.eventWaitGroups.WaitFor(blockingEvents)
-		eventToExec.Perform(context.WithValue(eventToExec.Context, timestone.ActionContextClockKey, clock.NewClock(eventToExec.Time)))
-		eventWaitGroup.Done()
+		s.watchdog.label(eventToExec)
+		startAt, missing := s.eventWaitGroups.WaitFor(blockingEvents, nil)
+		if len(missing) > 0 {
+			s.eventConfigs.Missed(eventToExec, missing)
+		}
+		execution.startAt(startAt)
+		s.trace.released(traceEvent, execution.Now())
+		s.pending.released(pendingEvent)
+		if !eventToExec.Resumption() {
+			s.observers.Released(eventToExec.Tags(), execution.Now())
+		}
+		for range releaseYields {
+			runtime.Gosched()
+		}
+		eventToExec.Perform(context.WithValue(eventToExec.Context, timestone.ActionContextClockKey, execution))
+		execution.elapse()
+		execution.finish()
+		close(performed)
 	}()
 
-	s.eventQueue.WaitForExpectedGenerators(expectedGenerators)
+	// A resumed action is known to park, so wait for it to be parked again
+	// or to finish in order to resume it in time. Waiting for the actions
+	// in flight to be idle covers resumed ones.
+	if s.inflight != nil {
+		s.await("the actions to be idle", s.inflight.wait)
+	} else if eventToExec.Resumption() {
+		s.await("the resumed action to be parked", closed(performed))
+	} else if s.decisions != nil && s.decisions.awaitIdle() {
+		s.await("the action to be idle", closed(idle))
+	}
+
+	if len(afterHooks) > 0 {
+		s.await("the action to be idle", closed(idle))
+		for _, hook := range afterHooks {
+			hook(eventInfo(eventToExec))
+		}
+	}
+
+	s.await("expected generators", func(stop <-chan struct{}) { s.eventQueue.WaitForExpectedGenerators(expectedGenerators, stop) })
+}
+
+// releaseYields returns how often the next Event yields the processor
+// after being released, to perturb the order in which Event s released
+// concurrently start.
+func (s *Scheduler) releaseYields() int {
+	if s.rand == nil {
+		return 0
+	}
+
+	return s.rand.IntN(maxReleaseYields + 1)
 }
 
 // PerformNow schedules action to be executed immediately, that is
@@ -151,14 +442,26 @@ func (s *Scheduler) execEvent(eventToExec *events.Event) {
 // generator which materializes a corresponding event to the Scheduler's
 // event queue.
 func (s *Scheduler) PerformNow(ctx context.Context, action timestone.Action, tags ...string) {
-	s.AddEventGenerators(events.NewOnceGenerator(ctx, action, s.clock.Now(), tags))
+	s.PerformNowWithHandle(ctx, action, tags...)
+}
+
+// PerformNowWithHandle is like PerformNow, but returns a timestone.Handle
+// to control action.
+func (s *Scheduler) PerformNowWithHandle(ctx context.Context, action timestone.Action, tags ...string) timestone.Handle {
+	return s.perform(ctx, events.NewOnceGenerator(ctx, action, s.clock.Now(), tags), tags)
 }
 
 // PerformAfter schedules an action to be run once after a delay
 // of duration. It adds a newMatching Event  generator which materializes a
 // corresponding event to the Scheduler's event queue.
 func (s *Scheduler) PerformAfter(ctx context.Context, action timestone.Action, interval time.Duration, tags ...string) {
-	s.AddEventGenerators(events.NewOnceGenerator(ctx, action, s.clock.Now().Add(interval), tags))
+	s.PerformAfterWithHandle(ctx, action, interval, tags...)
+}
+
+// PerformAfterWithHandle is like PerformAfter, but returns a
+// timestone.Handle to control action.
+func (s *Scheduler) PerformAfterWithHandle(ctx context.Context, action timestone.Action, interval time.Duration, tags ...string) timestone.Handle {
+	return s.perform(ctx, events.NewOnceGenerator(ctx, action, s.clock.Now().Add(interval), tags), tags)
 }
 
 // PerformRepeatedly schedules an action to be run every interval
@@ -167,12 +470,111 @@ func (s *Scheduler) PerformAfter(ctx context.Context, action timestone.Action, i
 // generator which materializes corresponding events to the Scheduler's
 // event queue.
 func (s *Scheduler) PerformRepeatedly(ctx context.Context, action timestone.Action, until *time.Time, interval time.Duration, tags ...string) {
-	s.AddEventGenerators(events.NewPeriodicGenerator(ctx, action, s.clock.Now(), until, interval, tags))
+	s.PerformRepeatedlyWithHandle(ctx, action, until, interval, tags...)
+}
+
+// PerformRepeatedlyWithHandle is like PerformRepeatedly, but returns a
+// timestone.Handle to control action.
+func (s *Scheduler) PerformRepeatedlyWithHandle(ctx context.Context, action timestone.Action, until *time.Time, interval time.Duration, tags ...string) timestone.Handle {
+	return s.perform(ctx, events.NewPeriodicGenerator(ctx, action, s.clock.Now(), until, interval, tags), tags)
+}
+
+// PerformOnSchedule schedules an action to be run at every firing of
+// the cron spec schedule, as understood by cron.Parse. It adds a new Event
+// generator which materializes corresponding events to the Scheduler's
+// event queue. An error is returned if schedule can't be
+// parsed.
+func (s *Scheduler) PerformOnSchedule(ctx context.Context, action timestone.Action, schedule string, tags ...string) error {
+	_, err := s.PerformOnScheduleWithHandle(ctx, action, schedule, tags...)
+	return err
+}
+
+// PerformOnScheduleWithHandle is like PerformOnSchedule, but returns a
+// timestone.Handle to control action.
+func (s *Scheduler) PerformOnScheduleWithHandle(ctx context.Context, action timestone.Action, schedule string, tags ...string) (timestone.Handle, error) {
+	cronSchedule, err := cron.Parse(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.PerformScheduledWithHandle(ctx, action, cronSchedule, tags...), nil
+}
+
+// PerformScheduled schedules an action to be run at every point in time
+// of schedule after the current time of the Scheduler's clock. It adds a
+// new Event generator which materializes corresponding events to the
+// Scheduler's event queue.
+func (s *Scheduler) PerformScheduled(ctx context.Context, action timestone.Action, schedule timestone.Schedule, tags ...string) {
+	s.PerformScheduledWithHandle(ctx, action, schedule, tags...)
+}
+
+// PerformScheduledWithHandle is like PerformScheduled, but returns a
+// timestone.Handle to control action.
+func (s *Scheduler) PerformScheduledWithHandle(ctx context.Context, action timestone.Action, schedule timestone.Schedule, tags ...string) timestone.Handle {
+	return s.perform(ctx, events.NewScheduleGenerator(ctx, action, s.clock.Now(), schedule, tags), tags)
+}
+
+// NewTimer returns a timestone.Timer that fires after duration. It is
+// backed by an events.OnceGenerator materializing an Event with tags,
+// which can be configured like the one of any other Action. Code
+// receiving from the Timer's channel outside of an Action should wait
+// for it after Forward.
+func (s *Scheduler) NewTimer(duration time.Duration, tags ...string) *timestone.Timer {
+	return timestone.NewTimer(s, duration, tags...)
+}
+
+// NewTicker returns a timestone.Ticker that ticks every interval. It is
+// backed by an events.PeriodicGenerator materializing Event s with tags.
+func (s *Scheduler) NewTicker(interval time.Duration, tags ...string) *timestone.Ticker {
+	return timestone.NewTicker(s, interval, tags...)
+}
+
+// AfterFunc returns a timestone.Timer that calls f after duration. It is
+// backed by an events.OnceGenerator materializing an Event with tags,
+// whose action calls f.
+func (s *Scheduler) AfterFunc(duration time.Duration, f func(), tags ...string) *timestone.Timer {
+	return timestone.NewTimerFunc(s, duration, f, tags...)
+}
+
+// Observe attaches observer to be notified about the lifecycle of the
+// Action s scheduled from now on, timed by the Scheduler's clock. Event s
+// added via AddEventGenerators are only reported when they are released.
+// The observer is notified while the run loop holds internal locks, so
+// it must not call the Scheduler.
+func (s *Scheduler) Observe(observer timestone.Observer) {
+	s.observers.Add(observer)
+}
+
+// perform adds generator materializing Event s with tags, decorated by
+// a handle which is returned to control it.
+func (s *Scheduler) perform(ctx context.Context, generator events.ResettableGenerator, tags []string) timestone.Handle {
+	return s.add(ctx, generator, tags, &s.observers)
+}
+
+// add adds generator like perform, notifying observers, which is nil for
+// generators that aren't to be observed.
+func (s *Scheduler) add(ctx context.Context, generator events.ResettableGenerator, tags []string, observers *internal.Observers) *handle {
+	h := newHandle(ctx, s, generator, tags, observers)
+
+	if h.Finished() {
+		h.Exhaust()
+	} else {
+		observers.Scheduled(tags, h.Peek().Time)
+	}
+
+	s.AddEventGenerators(h)
+
+	return h
 }
 
 // AddEventGenerators is used by the Perform... methods of the Scheduler.
 // It can be used to pass a custom event generator if Timestone is used
 // to run event-based simulations.
+//
+// A generator whose next Event carries the context.Context passed to an
+// action is attributed to the Event performing it, e.g. in the Trace.
+// Added while the action is in flight, it is known to a run loop waiting
+// for the action as with RunUntilIdle.
 func (s *Scheduler) AddEventGenerators(generators ...events.Generator) {
 	s.eventGeneratorsMu.Lock()
 	defer s.eventGeneratorsMu.Unlock()
@@ -182,6 +584,7 @@ func (s *Scheduler) AddEventGenerators(generators ...events.Generator) {
 			continue
 		}
 
+		s.trace.added(s, generator)
 		s.eventQueue.Add(generator)
 	}
 }
@@ -3,12 +3,15 @@ package simulation
 import (
 	"context"
 	"fmt"
+	"github.com/metamogul/timestone/v2/cron"
+	"github.com/metamogul/timestone/v2/schedule"
 	"github.com/metamogul/timestone/v2/simulation/config"
 	"github.com/metamogul/timestone/v2/simulation/internal/clock"
 	"github.com/metamogul/timestone/v2/simulation/internal/events"
 	"github.com/metamogul/timestone/v2/simulation/internal/waitgroups"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -52,6 +55,92 @@ func TestScheduler_ConfigureEvent(t *testing.T) {
 	NewScheduler(now).ConfigureEvents(config.Config{Tags: []string{"test"}})
 }
 
+func TestScheduler_ValidateConfigs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	require.NoError(t, s.ConfigureEvents(
+		config.Config{Tags: []string{"a"}, WaitFor: []config.Event{config.All{Tags: []string{"b"}}}},
+		config.Config{Tags: []string{"unused"}},
+	))
+	require.NoError(t, s.ValidateConfigs())
+
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "b")
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "a")
+	s.Forward(time.Minute)
+
+	var validationError *config.ValidationError
+	require.ErrorAs(t, s.ValidateConfigs(), &validationError)
+	require.Len(t, validationError.Problems, 1)
+	require.Equal(t, config.ProblemUnmatched, validationError.Problems[0].Kind)
+
+	require.ErrorAs(t, s.ConfigureEvents(config.Config{Tags: []string{"b"}, WaitFor: []config.Event{config.All{Tags: []string{"a"}}}}), &validationError)
+	require.Len(t, validationError.Problems, 1)
+	require.Equal(t, config.ProblemWaitForCycle, validationError.Problems[0].Kind)
+
+	require.ErrorAs(t, s.ValidateConfigs(), &validationError)
+	require.Equal(t, config.ProblemWaitForCycle, validationError.Problems[0].Kind)
+	require.Equal(t,
+		"3 invalid config(s):\n"+
+			"\tWaitFor cycle: config tagged [a] waits for config.All{Tags:[b] Selector:}, config tagged [b] waits for config.All{Tags:[a] Selector:}\n"+
+			"\tunmatched: config tagged [unused] hasn't configured any event\n"+
+			"\tunmatched: config tagged [b] hasn't configured any event",
+		validationError.Error(),
+	)
+	// Only the problems involving the configs passed are reported.
+	require.NoError(t, s.ConfigureEvents(config.Config{Tags: []string{"c"}}))
+}
+
+func TestScheduler_ValidateConfigs_invalidSelector(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	var validationError *config.ValidationError
+	require.ErrorAs(t, s.ConfigureEvents(
+		config.Config{Selector: "(("},
+		config.Config{Tags: []string{"a"}, WaitFor: []config.Event{config.All{Selector: "b &&"}}},
+	), &validationError)
+	require.Len(t, validationError.Problems, 2)
+	require.Equal(t, config.ProblemInvalidSelector, validationError.Problems[0].Kind)
+	require.Equal(t, config.ProblemInvalidSelector, validationError.Problems[1].Kind)
+
+	require.NoError(t, s.ConfigureEvents(config.Config{Tags: []string{"c"}}))
+	require.ErrorAs(t, s.ValidateConfigs(), &validationError)
+	require.Len(t, validationError.Problems, 2)
+
+	performed := false
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) { performed = true }), "a")
+	s.WaitFor(config.All{Selector: "(("})
+	s.Forward(time.Minute)
+	require.True(t, performed)
+}
+
+func TestScheduler_ValidateConfigs_missingTarget(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	require.NoError(t, s.ConfigureEvents(config.Config{Tags: []string{"a"}, WaitFor: []config.Event{config.All{Tags: []string{"missing"}}}}))
+
+	performed := false
+	s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) { performed = true }), time.Minute, "a")
+	s.Forward(time.Hour)
+	require.True(t, performed)
+
+	var validationError *config.ValidationError
+	require.ErrorAs(t, s.ValidateConfigs(), &validationError)
+	require.Equal(t,
+		"1 invalid config(s):\n"+
+			"\tmissing target: config tagged [a] waits for config.All{Tags:[missing] Selector:}, but no such event existed when the event tagged [a] at 2024-01-01 12:01:00 +0000 UTC waited for it",
+		validationError.Error(),
+	)
+}
+
 func TestScheduler_ForwardOne(t *testing.T) {
 	t.Parallel()
 
@@ -172,7 +261,7 @@ func TestScheduler_WaitFor(t *testing.T) {
 		wg2.Done()
 	}()
 
-	s.eventWaitGroups.WaitFor([]config.Event{config.All{Tags: []string{"group"}}})
+	s.WaitFor(config.All{Tags: []string{"group"}})
 }
 
 func TestScheduler_Wait(t *testing.T) {
@@ -188,7 +277,7 @@ func TestScheduler_Wait(t *testing.T) {
 		go func() { wg.Done() }()
 	}
 
-	s.eventWaitGroups.Wait()
+	s.eventWaitGroups.Wait(nil)
 }
 
 func TestScheduler_Forward(t *testing.T) {
@@ -316,7 +405,7 @@ func TestScheduler_Forward_Recursive(t *testing.T) {
 
 	s.ConfigureEvents(config.Config{
 		Tags: []string{"outerAction"},
-		Adds: []*config.Generator{{[]string{"innerAction"}, 1}},
+		Adds: []*config.Generator{{Tags: []string{"innerAction"}, Count: 1}},
 	})
 
 	s.PerformAfter(context.Background(), outerAction, time.Second, "outerAction")
@@ -330,6 +419,89 @@ func TestScheduler_Forward_Recursive(t *testing.T) {
 
 }
 
+func TestScheduler_ForwardTo(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	s.PerformRepeatedly(context.Background(), recorder.action(), nil, time.Minute, "job")
+
+	s.ForwardTo(now.Add(2 * time.Minute))
+	require.Equal(t, now.Add(2*time.Minute), s.Now())
+	require.ElementsMatch(t, []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)}, recorder.times())
+
+	require.Panics(t, func() { s.ForwardTo(now) })
+}
+
+func TestScheduler_ForwardInstant(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now, RunUntilIdle())
+
+	s.ForwardInstant()
+	require.Equal(t, now, s.Now())
+
+	s.PerformAfter(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		s.PerformNow(ctx, recorder.action(), "spawned")
+		s.PerformAfter(ctx, recorder.action(), time.Minute, "later")
+	}), time.Hour, "parent")
+	s.PerformAfter(context.Background(), recorder.action(), 2*time.Hour, "other")
+
+	s.ForwardInstant()
+	require.Equal(t, now.Add(time.Hour), s.Now())
+	require.Equal(t, []time.Time{now.Add(time.Hour)}, recorder.times())
+
+	s.ForwardInstant()
+	require.Equal(t, now.Add(time.Hour+time.Minute), s.Now())
+	require.Len(t, recorder.times(), 2)
+}
+
+func TestScheduler_ForwardN(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &executionRecorder{}
+
+	s := NewScheduler(now)
+	until := now.Add(4 * time.Minute)
+	s.PerformRepeatedly(context.Background(), recorder.action(), &until, time.Minute, "job")
+
+	s.ForwardN(2)
+	require.Equal(t, now.Add(2*time.Minute), s.Now())
+	require.Len(t, recorder.times(), 2)
+
+	s.ForwardN(5)
+	require.Equal(t, now.Add(3*time.Minute), s.Now())
+	require.Len(t, recorder.times(), 3)
+}
+
+func TestScheduler_ForwardUntil(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var attempts atomic.Int32
+	s := NewScheduler(now)
+	s.PerformRepeatedly(context.Background(), timestone.SimpleAction(func(context.Context) {
+		attempts.Add(1)
+	}), nil, 30*time.Second, "retry")
+
+	require.True(t, s.ForwardUntil(func() bool { return attempts.Load() == 3 }, time.Hour))
+	require.Equal(t, now.Add(90*time.Second), s.Now())
+
+	require.True(t, s.ForwardUntil(func() bool { return attempts.Load() == 3 }, time.Hour))
+	require.Equal(t, now.Add(90*time.Second), s.Now())
+
+	require.False(t, s.ForwardUntil(func() bool { return attempts.Load() == 10 }, time.Minute))
+	require.Equal(t, now.Add(150*time.Second), s.Now())
+	require.Equal(t, int32(5), attempts.Load())
+}
+
 func TestScheduler_execNextEvent(t *testing.T) {
 	t.Parallel()
 
@@ -385,7 +557,7 @@ func TestScheduler_execNextEvent(t *testing.T) {
 			if gotShouldContinue := s.execNextEvent(targetTime); gotShouldContinue != tt.wantShouldContinue {
 				t.Errorf("performNextEvent() = %v, want %v", gotShouldContinue, tt.wantShouldContinue)
 			}
-			s.eventWaitGroups.Wait()
+			s.eventWaitGroups.Wait(nil)
 
 			if tt.wantShouldContinue == true {
 				require.Equal(t, now.Add(time.Second), s.clock.Now())
@@ -417,7 +589,7 @@ func TestScheduler_execEvent(t *testing.T) {
 		eventToExec := events.NewEvent(context.Background(), mockAction, now.Add(time.Minute), []string{"test"})
 		eventConfig := config.Config{
 			Tags: []string{"test"},
-			Adds: []*config.Generator{{[]string{"scheduledByTest"}, 1}},
+			Adds: []*config.Generator{{Tags: []string{"scheduledByTest"}, Count: 1}},
 		}
 
 		s.eventConfigs.Set(eventConfig)
@@ -483,6 +655,104 @@ func TestScheduler_PerformRepeatedly(t *testing.T) {
 	require.False(t, s.eventQueue.Finished())
 }
 
+func TestScheduler_PerformOnSchedule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	err := s.PerformOnSchedule(context.Background(), timestone.NewMockAction(t), "@hourly", "mockAction")
+	require.NoError(t, err)
+
+	require.False(t, s.eventQueue.Finished())
+	require.Equal(t, now.Add(time.Hour), s.eventQueue.Peek().Time)
+}
+
+func TestScheduler_PerformOnSchedule_invalidSchedule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	err := s.PerformOnSchedule(context.Background(), timestone.NewMockAction(t), "@fortnightly", "mockAction")
+	require.ErrorIs(t, err, cron.ErrInvalidSpec)
+
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestScheduler_PerformOnSchedule_daylightSavingTime(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	now := time.Date(2024, 3, 30, 0, 0, 0, 0, berlin)
+
+	mu := sync.Mutex{}
+	executionTimes := make([]time.Time, 0)
+
+	s := NewScheduler(now)
+
+	err = s.PerformOnSchedule(
+		context.Background(),
+		timestone.SimpleAction(func(ctx context.Context) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			executionTimes = append(executionTimes, ctx.Value(timestone.ActionContextClockKey).(timestone.Clock).Now())
+		}),
+		"0 3 * * *",
+		"nightlyJob",
+	)
+	require.NoError(t, err)
+
+	for range 3 {
+		s.Forward(24 * time.Hour)
+	}
+
+	// The second day is one hour shorter
+	require.Equal(t, []time.Time{
+		time.Date(2024, 3, 30, 3, 0, 0, 0, berlin),
+		time.Date(2024, 3, 31, 3, 0, 0, 0, berlin),
+		time.Date(2024, 4, 1, 3, 0, 0, 0, berlin),
+	}, executionTimes)
+}
+
+func TestScheduler_PerformScheduled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mu := sync.Mutex{}
+	executionTimes := make([]time.Time, 0)
+
+	s := NewScheduler(now)
+
+	s.PerformScheduled(
+		context.Background(),
+		timestone.SimpleAction(func(ctx context.Context) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			executionTimes = append(executionTimes, ctx.Value(timestone.ActionContextClockKey).(timestone.Clock).Now())
+		}),
+		schedule.Limit(schedule.Exponential(now, time.Second, 2, 0), now, 4),
+		"backoff",
+	)
+
+	s.Forward(time.Minute)
+
+	require.ElementsMatch(t, []time.Time{
+		now.Add(1 * time.Second),
+		now.Add(3 * time.Second),
+		now.Add(7 * time.Second),
+		now.Add(15 * time.Second),
+	}, executionTimes)
+	require.True(t, s.eventQueue.Finished())
+}
+
 func TestScheduler_AddEventGenerators(t *testing.T) {
 	t.Parallel()
 
@@ -516,3 +786,57 @@ func TestScheduler_AddEventGenerators(t *testing.T) {
 
 	require.False(t, s.eventQueue.Finished())
 }
+
+// recordingObserver records the notifications of a timestone.Observer.
+type recordingObserver struct {
+	notifications []string
+	mu            sync.Mutex
+}
+
+func (o *recordingObserver) record(kind string, tags []string, at time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.notifications = append(o.notifications, fmt.Sprintf("%s %v at %s", kind, tags, at.Format(time.TimeOnly)))
+}
+
+func (o *recordingObserver) OnScheduled(tags []string, at time.Time) { o.record("scheduled", tags, at) }
+func (o *recordingObserver) OnReleased(tags []string, at time.Time)  { o.record("released", tags, at) }
+func (o *recordingObserver) OnStarted(tags []string, at time.Time)   { o.record("started", tags, at) }
+func (o *recordingObserver) OnFinished(tags []string, at time.Time)  { o.record("finished", tags, at) }
+func (o *recordingObserver) OnCancelled(tags []string, at time.Time) { o.record("cancelled", tags, at) }
+func (o *recordingObserver) OnPanicked(tags []string, at time.Time, value any) {
+	o.record(fmt.Sprintf("panicked with %v", value), tags, at)
+}
+
+func TestScheduler_Observe(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now, RunUntilIdle())
+
+	observer := &recordingObserver{}
+	s.Observe(observer)
+
+	s.PerformRepeatedly(ctx, timestone.SimpleAction(func(context.Context) {}), nil, time.Second, "periodic")
+	cancelled := s.PerformAfterWithHandle(ctx, timestone.NewMockAction(t), time.Second, "cancelled")
+	cancelled.Cancel()
+
+	s.Forward(2 * time.Second)
+
+	require.Equal(t, []string{
+		"scheduled [periodic] at 00:00:01",
+		"scheduled [cancelled] at 00:00:01",
+		"cancelled [cancelled] at 00:00:00",
+		"scheduled [periodic] at 00:00:02",
+		"released [periodic] at 00:00:01",
+		"started [periodic] at 00:00:01",
+		"finished [periodic] at 00:00:01",
+		"scheduled [periodic] at 00:00:03",
+		"released [periodic] at 00:00:02",
+		"started [periodic] at 00:00:02",
+		"finished [periodic] at 00:00:02",
+	}, observer.notifications)
+}
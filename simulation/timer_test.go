@@ -0,0 +1,193 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_NewTimer(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	timer := s.NewTimer(time.Minute, "timer")
+
+	s.Forward(59 * time.Second)
+	require.Empty(t, timer.C)
+
+	s.Forward(time.Second)
+	require.Equal(t, now.Add(time.Minute), <-timer.C)
+	require.False(t, timer.Stop())
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestScheduler_NewTimer_select(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	timer := s.NewTimer(time.Minute, "timer")
+
+	var received time.Time
+	handle := s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		select {
+		case received = <-timer.C:
+		case <-ctx.Done():
+		}
+	}), "waiting")
+
+	s.Forward(time.Minute)
+
+	<-handle.Done()
+	require.Equal(t, now.Add(time.Minute), received)
+}
+
+func TestTimer_Stop(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	timer := s.NewTimer(time.Minute, "timer")
+
+	s.Forward(30 * time.Second)
+	require.True(t, timer.Stop())
+	require.False(t, timer.Stop())
+
+	s.Forward(time.Hour)
+	require.Empty(t, timer.C)
+	require.True(t, s.eventQueue.Finished())
+}
+
+func TestTimer_Stop_whileFiring(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	timer := s.NewTimer(time.Minute, "timer")
+
+	// The Event firing the Timer has been released, but its action hasn't
+	// fired it yet.
+	stopped := false
+	_, err := s.Break(Breakpoint{Selector: "timer"}, func(EventInfo) {
+		stopped = timer.Stop()
+	})
+	require.NoError(t, err)
+
+	s.Forward(time.Minute)
+	require.True(t, stopped)
+	require.Empty(t, timer.C)
+}
+
+func TestTimer_Reset(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name       string
+		forward    time.Duration
+		wantActive bool
+	}{
+		{
+			name:       "active",
+			forward:    30 * time.Second,
+			wantActive: true,
+		},
+		{
+			name:       "fired but not received",
+			forward:    time.Minute,
+			wantActive: false,
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+			s := NewScheduler(now)
+			timer := s.NewTimer(time.Minute, "timer")
+
+			s.Forward(tt.forward)
+			require.Equal(t, tt.wantActive, timer.Reset(time.Minute))
+			require.Empty(t, timer.C)
+
+			s.Forward(time.Minute)
+			require.Equal(t, now.Add(tt.forward+time.Minute), <-timer.C)
+		})
+	}
+}
+
+func TestScheduler_AfterFunc(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+
+	called := false
+	timer := s.AfterFunc(time.Minute, func() { called = true }, "timer")
+	require.Nil(t, timer.C)
+
+	s.Forward(59 * time.Second)
+	require.False(t, called)
+
+	s.Forward(time.Second)
+	require.True(t, called)
+	require.False(t, timer.Stop())
+}
+
+func TestScheduler_NewTicker(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	ticker := s.NewTicker(time.Minute, "ticker")
+
+	s.Forward(time.Minute)
+	require.Equal(t, now.Add(time.Minute), <-ticker.C)
+
+	// Ticks that haven't been received are replaced by the following one.
+	s.Forward(2 * time.Minute)
+	require.Equal(t, now.Add(3*time.Minute), <-ticker.C)
+	require.Empty(t, ticker.C)
+
+	ticker.Stop()
+	s.Forward(time.Hour)
+	require.Empty(t, ticker.C)
+	require.True(t, s.eventQueue.Finished())
+
+	require.Panics(t, func() { s.NewTicker(0) })
+}
+
+func TestTicker_Reset(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now)
+	ticker := s.NewTicker(time.Minute, "ticker")
+
+	s.Forward(time.Minute)
+	ticker.Reset(time.Hour)
+	require.Empty(t, ticker.C)
+
+	s.Forward(time.Hour)
+	require.Equal(t, now.Add(time.Minute+time.Hour), <-ticker.C)
+
+	ticker.Stop()
+	ticker.Reset(time.Minute)
+
+	s.Forward(time.Minute)
+	require.Equal(t, now.Add(time.Minute+time.Hour+time.Minute), <-ticker.C)
+
+	require.Panics(t, func() { ticker.Reset(0) })
+}
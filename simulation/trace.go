@@ -0,0 +1,351 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+)
+
+// Trace records the Event s executed by a Scheduler created with
+// RecordTrace, in the order the run loop has executed them.
+type Trace struct {
+	events       []*TraceEvent
+	releaseCount int
+	mu           sync.Mutex
+}
+
+// TraceEvent is the record of an Event executed by a Scheduler.
+type TraceEvent struct {
+	// Tags are the tags of the Event.
+	Tags []string
+	// Resumption is true if the Event resumed a parked action.
+	Resumption bool
+	// Time is the time the Event has been scheduled for.
+	Time time.Time
+	// ReleasedAt is the time of the Scheduler's clock at which the action
+	// started to be performed, once the Event s it waited for had
+	// completed.
+	ReleasedAt time.Time
+	// CompletedAt is the time of the Scheduler's clock at which the action
+	// returned or was parked, including the time it takes as configured
+	// by config.Config.Duration. It is zero if the Event hasn't completed.
+	CompletedAt time.Time
+	// Started and Ended are the real times between which the Event has
+	// been executed, from the run loop starting it until it has completed.
+	Started, Ended time.Time
+	// WaitedFor are the events the Event waited for, as configured by
+	// config.Config.WaitFor.
+	WaitedFor []config.Event
+	// Added holds the tags of the Event s of the generators added by the
+	// action through the Perform... methods of the Scheduler, using the
+	// context.Context passed to it.
+	Added [][]string
+
+	// releaseOrder orders the TraceEvent s released at the same time of
+	// the Scheduler's clock. It is 0 if the Event hasn't been released.
+	releaseOrder int
+}
+
+// Events returns a copy of the TraceEvent s recorded so far.
+func (t *Trace) Events() []TraceEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]TraceEvent, len(t.events))
+	for i, event := range t.events {
+		result[i] = *event
+		result[i].Added = slices.Clone(event.Added)
+	}
+
+	return result
+}
+
+// WriteChromeJSON writes the Trace to w in the JSON format of Chrome
+// trace events, to be viewed e.g. with https://ui.perfetto.dev. The
+// timeline of the Scheduler's clock and the one of real time are shown
+// as separate processes, with a thread per set of tags.
+func (t *Trace) WriteChromeJSON(w io.Writer) error {
+	traceEvents := t.Events()
+
+	var virtualOrigin, realOrigin time.Time
+	for _, event := range traceEvents {
+		if virtualOrigin.IsZero() || event.Time.Before(virtualOrigin) {
+			virtualOrigin = event.Time
+		}
+		if realOrigin.IsZero() || event.Started.Before(realOrigin) {
+			realOrigin = event.Started
+		}
+	}
+
+	chromeEvents := []chromeEvent{
+		{Name: "process_name", Ph: "M", Pid: chromeVirtualPid, Args: map[string]any{"name": "virtual time"}},
+		{Name: "process_name", Ph: "M", Pid: chromeRealPid, Args: map[string]any{"name": "real time"}},
+	}
+
+	var lanes []string
+	for _, event := range traceEvents {
+		name := strings.Join(event.Tags, ",")
+
+		lane := slices.Index(lanes, name)
+		if lane < 0 {
+			lanes = append(lanes, name)
+			lane = len(lanes) - 1
+
+			for _, pid := range []int{chromeVirtualPid, chromeRealPid} {
+				chromeEvents = append(chromeEvents, chromeEvent{Name: "thread_name", Ph: "M", Pid: pid, Tid: lane + 1, Args: map[string]any{"name": name}})
+			}
+		}
+
+		if event.ReleasedAt.After(event.Time) {
+			chromeEvents = append(chromeEvents, chromeEvent{
+				Name: "waiting",
+				Cat:  "waiting",
+				Ph:   "X",
+				Ts:   microseconds(event.Time.Sub(virtualOrigin)),
+				Dur:  microseconds(event.ReleasedAt.Sub(event.Time)),
+				Pid:  chromeVirtualPid,
+				Tid:  lane + 1,
+			})
+		}
+
+		category := "event"
+		if event.Resumption {
+			category = "resumption"
+		}
+
+		args := map[string]any{
+			"scheduled": event.Time.Format(time.RFC3339Nano),
+			"released":  event.ReleasedAt.Format(time.RFC3339Nano),
+		}
+		if !event.CompletedAt.IsZero() {
+			args["completed"] = event.CompletedAt.Format(time.RFC3339Nano)
+		}
+		if len(event.WaitedFor) > 0 {
+			waitedFor := make([]string, len(event.WaitedFor))
+			for i, waitedForEvent := range event.WaitedFor {
+				waitedFor[i] = fmt.Sprintf("%T%+v", waitedForEvent, waitedForEvent)
+			}
+			args["waitedFor"] = waitedFor
+		}
+		if len(event.Added) > 0 {
+			args["added"] = event.Added
+		}
+
+		virtualDuration := time.Duration(0)
+		if !event.CompletedAt.IsZero() {
+			virtualDuration = event.CompletedAt.Sub(event.ReleasedAt)
+		}
+
+		realDuration := time.Duration(0)
+		if !event.Ended.IsZero() {
+			realDuration = event.Ended.Sub(event.Started)
+		}
+
+		chromeEvents = append(chromeEvents,
+			chromeEvent{
+				Name: name,
+				Cat:  category,
+				Ph:   "X",
+				Ts:   microseconds(event.ReleasedAt.Sub(virtualOrigin)),
+				Dur:  microseconds(virtualDuration),
+				Pid:  chromeVirtualPid,
+				Tid:  lane + 1,
+				Args: args,
+			},
+			chromeEvent{
+				Name: name,
+				Cat:  category,
+				Ph:   "X",
+				Ts:   microseconds(event.Started.Sub(realOrigin)),
+				Dur:  microseconds(realDuration),
+				Pid:  chromeRealPid,
+				Tid:  lane + 1,
+				Args: args,
+			},
+		)
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeEvent `json:"traceEvents"`
+		DisplayTimeUnit string        `json:"displayTimeUnit"`
+	}{chromeEvents, "ms"})
+}
+
+const (
+	chromeVirtualPid = 1
+	chromeRealPid    = 2
+)
+
+// chromeEvent is an event of the Chrome trace event format.
+type chromeEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"`
+	Dur  float64        `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// add records that event is executed, waiting for blockingEvents.
+func (t *Trace) add(event *events.Event, blockingEvents []config.Event) *TraceEvent {
+	traceEvent := &TraceEvent{
+		Tags:       event.Tags(),
+		Resumption: event.Resumption(),
+		Time:       event.Time,
+		WaitedFor:  blockingEvents,
+		Started:    time.Now(),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events = append(t.events, traceEvent)
+
+	return traceEvent
+}
+
+// released records that the action of traceEvent starts to be performed
+// at releasedAt.
+func (t *Trace) released(traceEvent *TraceEvent, releasedAt time.Time) {
+	if traceEvent == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.releaseCount++
+	traceEvent.ReleasedAt = releasedAt
+	traceEvent.releaseOrder = t.releaseCount
+}
+
+// completed records that traceEvent has completed at completedAt.
+func (t *Trace) completed(traceEvent *TraceEvent, completedAt time.Time) {
+	if traceEvent == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	traceEvent.CompletedAt = completedAt
+	traceEvent.Ended = time.Now()
+}
+
+// added records that an action has added generator, if the context.Context
+// of its next Event carries the execution of the action, unless it is
+// resuming a parked action.
+func (t *Trace) added(scheduler *Scheduler, generator events.Generator) {
+	if t == nil || generator.Finished() {
+		return
+	}
+
+	nextEvent := generator.Peek()
+	if nextEvent.Resumption() {
+		return
+	}
+
+	execution, ok := executionOf(nextEvent.Context, scheduler)
+	if !ok || execution.traceEvent == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	execution.traceEvent.Added = append(execution.traceEvent.Added, nextEvent.Tags())
+}
+
+// String returns a normalized textual representation of the Trace, which
+// only depends on the order of its TraceEvent s and the time of the
+// Scheduler's clock, relative to the time of the first Event. Each
+// TraceEvent is numbered, and refers to the TraceEvent that added its
+// generator or that it resumes.
+func (t *Trace) String() string {
+	traceEvents := t.Events()
+	if len(traceEvents) == 0 {
+		return ""
+	}
+
+	origin := traceEvents[0].Time
+	for _, event := range traceEvents {
+		if event.Time.Before(origin) {
+			origin = event.Time
+		}
+	}
+	offset := func(at time.Time) string { return "+" + at.Sub(origin).String() }
+
+	// pendingAdded holds the tags of the Event s added by each
+	// TraceEvent that haven't been found in the Trace yet.
+	pendingAdded := make([][][]string, len(traceEvents))
+	for i, event := range traceEvents {
+		pendingAdded[i] = slices.Clone(event.Added)
+	}
+
+	var b strings.Builder
+	for i, event := range traceEvents {
+		fmt.Fprintf(&b, "#%d %s %s", i+1, offset(event.Time), strings.Join(event.Tags, ","))
+
+		if event.Resumption {
+			if j := resumedBy(traceEvents, i); j >= 0 {
+				fmt.Fprintf(&b, " resumes #%d", j+1)
+			}
+		} else {
+		addedBy:
+			for j := range i {
+				for k, added := range pendingAdded[j] {
+					if slices.Equal(added, event.Tags) {
+						pendingAdded[j] = slices.Delete(pendingAdded[j], k, k+1)
+						fmt.Fprintf(&b, " added by #%d", j+1)
+						break addedBy
+					}
+				}
+			}
+		}
+		b.WriteString("\n")
+
+		for _, waitedForEvent := range event.WaitedFor {
+			fmt.Fprintf(&b, "\twaited for %T%+v\n", waitedForEvent, waitedForEvent)
+		}
+		if !event.ReleasedAt.Equal(event.Time) {
+			fmt.Fprintf(&b, "\treleased %s\n", offset(event.ReleasedAt))
+		}
+		switch {
+		case event.CompletedAt.IsZero():
+			b.WriteString("\tnot completed\n")
+		case !event.CompletedAt.Equal(event.ReleasedAt):
+			fmt.Fprintf(&b, "\tcompleted %s\n", offset(event.CompletedAt))
+		}
+	}
+
+	return b.String()
+}
+
+// resumedBy returns the index of the TraceEvent whose action the
+// TraceEvent at i resumes, or -1 if it doesn't resume one.
+func resumedBy(traceEvents []TraceEvent, i int) int {
+	if !traceEvents[i].Resumption {
+		return -1
+	}
+
+	for j := i - 1; j >= 0; j-- {
+		if !traceEvents[j].Resumption && slices.Equal(traceEvents[j].Tags, traceEvents[i].Tags) {
+			return j
+		}
+	}
+
+	return -1
+}
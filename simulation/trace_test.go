@@ -0,0 +1,135 @@
+package simulation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordTrace(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now, RecordTrace(), RunUntilIdle())
+	s.ConfigureEvents(
+		config.Config{Tags: []string{"parent"}, Duration: config.Fixed(time.Minute)},
+		config.Config{Tags: []string{"other"}, WaitFor: []config.Event{config.All{Tags: []string{"parent"}}}},
+	)
+
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		s.PerformAfter(ctx, timestone.SimpleAction(func(context.Context) {}), time.Hour, "child")
+	}), "parent")
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "other")
+
+	s.Forward(30 * time.Minute)
+
+	traceEvents := s.Trace().Events()
+	require.Len(t, traceEvents, 4)
+
+	require.Equal(t, []string{"parent"}, traceEvents[0].Tags)
+	require.False(t, traceEvents[0].Resumption)
+	require.Equal(t, now, traceEvents[0].Time)
+	require.Equal(t, now, traceEvents[0].ReleasedAt)
+	require.Equal(t, now.Add(time.Minute), traceEvents[0].CompletedAt)
+	require.Equal(t, [][]string{{"child"}}, traceEvents[0].Added)
+	require.False(t, traceEvents[0].Started.After(traceEvents[0].Ended))
+
+	require.Equal(t, []string{"other"}, traceEvents[1].Tags)
+	require.Equal(t, now, traceEvents[1].Time)
+	require.Equal(t, now.Add(time.Minute), traceEvents[1].ReleasedAt)
+	require.Equal(t, now.Add(time.Minute), traceEvents[1].CompletedAt)
+	require.False(t, traceEvents[1].Started.After(traceEvents[1].Ended))
+	require.Equal(t, []config.Event{config.All{Tags: []string{"parent"}}}, traceEvents[1].WaitedFor)
+
+	// The parent completes, and the other event starts, after a minute,
+	// as resumed by events with their tags.
+	require.ElementsMatch(t, [][]string{{"parent"}, {"other"}}, [][]string{traceEvents[2].Tags, traceEvents[3].Tags})
+	require.True(t, traceEvents[2].Resumption)
+	require.True(t, traceEvents[3].Resumption)
+
+	require.Equal(t, traceEvents, s.Trace().Events())
+}
+
+func TestRecordTrace_disabled(t *testing.T) {
+	t.Parallel()
+
+	s := NewScheduler(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}))
+	s.Forward(time.Minute)
+
+	require.Nil(t, s.Trace())
+}
+
+func TestTrace_WriteChromeJSON(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now, RecordTrace(), RunUntilIdle())
+	s.ConfigureEvents(
+		config.Config{Tags: []string{"first"}, Duration: config.Fixed(time.Second)},
+		config.Config{Tags: []string{"second"}, WaitFor: []config.Event{config.All{Tags: []string{"first"}}}},
+	)
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "first")
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "second")
+	s.Forward(time.Minute)
+
+	var b bytes.Buffer
+	require.NoError(t, s.Trace().WriteChromeJSON(&b))
+
+	var chromeTrace struct {
+		TraceEvents []chromeEvent `json:"traceEvents"`
+	}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &chromeTrace))
+
+	var virtualSlices []chromeEvent
+	for _, chromeEvent := range chromeTrace.TraceEvents {
+		if chromeEvent.Ph == "X" && chromeEvent.Pid == chromeVirtualPid && chromeEvent.Cat != "resumption" {
+			chromeEvent.Args = nil
+			virtualSlices = append(virtualSlices, chromeEvent)
+		}
+	}
+
+	require.Equal(t, []chromeEvent{
+		{Name: "first", Cat: "event", Ph: "X", Ts: 0, Dur: 1e6, Pid: chromeVirtualPid, Tid: 1},
+		{Name: "waiting", Cat: "waiting", Ph: "X", Ts: 0, Dur: 1e6, Pid: chromeVirtualPid, Tid: 2},
+		{Name: "second", Cat: "event", Ph: "X", Ts: 1e6, Dur: 0, Pid: chromeVirtualPid, Tid: 2},
+	}, virtualSlices)
+}
+
+func TestTrace_String(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewScheduler(now, RecordTrace(), RunUntilIdle())
+	s.ConfigureEvents(
+		config.Config{Tags: []string{"parent"}, Duration: config.Fixed(time.Minute)},
+		config.Config{Tags: []string{"other"}, WaitFor: []config.Event{config.All{Tags: []string{"parent"}}}},
+	)
+
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		s.PerformAfter(ctx, timestone.SimpleAction(func(context.Context) {}), time.Hour, "child")
+	}), "parent")
+	s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "other")
+
+	s.Forward(2 * time.Hour)
+
+	require.Equal(t, `#1 +0s parent
+	completed +1m0s
+#2 +0s other
+	waited for config.All{Tags:[parent] Selector:}
+	released +1m0s
+#3 +1m0s parent resumes #1
+#4 +1m0s other resumes #2
+	waited for config.All{Tags:[parent] Selector:}
+#5 +1h0m0s child added by #1
+`, s.Trace().String())
+}
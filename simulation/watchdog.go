@@ -0,0 +1,139 @@
+package simulation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+
+	configinternal "github.com/metamogul/timestone/v2/simulation/internal/config"
+	"github.com/metamogul/timestone/v2/simulation/internal/events"
+)
+
+// watchdogLabel is the pprof label identifying the goroutines performing
+// the action of an Event by its tags.
+const watchdogLabel = "timestone.event"
+
+// watchdog fails the run loop of a Scheduler created with Watchdog once
+// it has been blocked for longer than budget, reporting why.
+type watchdog struct {
+	t      testing.TB
+	budget time.Duration
+}
+
+// label labels the calling goroutine, and those it starts, with the tags
+// of event, so that they can be found in the report.
+func (w *watchdog) label(event *events.Event) {
+	if w == nil {
+		return
+	}
+
+	pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels(watchdogLabel, strings.Join(event.Tags(), ","))))
+}
+
+// await calls wait, which blocks the run loop for the reason given by
+// what until it returns or stop is closed. If s has been created with
+// Watchdog and wait doesn't return within its budget, stop is closed and
+// await fails the test with a report of what blocks the run loop,
+// stopping the goroutine running the run loop.
+func (s *Scheduler) await(what string, wait func(stop <-chan struct{})) {
+	if s.watchdog == nil {
+		wait(nil)
+		return
+	}
+
+	stop := make(chan struct{})
+	timer := time.AfterFunc(s.watchdog.budget, func() { close(stop) })
+
+	wait(stop)
+
+	if !timer.Stop() {
+		s.watchdog.t.Fatal(s.watchdog.report(s, what))
+	}
+}
+
+// closed returns a wait for Scheduler.await that returns once c has been
+// closed.
+func closed(c <-chan struct{}) func(stop <-chan struct{}) {
+	return func(stop <-chan struct{}) {
+		select {
+		case <-c:
+		case <-stop:
+		}
+	}
+}
+
+// report describes the Event s whose actions haven't completed, the
+// expected generators that haven't been added and the goroutines of the
+// actions.
+func (w *watchdog) report(s *Scheduler, what string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Run loop blocked for more than %v waiting for %s at %v\n", w.budget, what, s.Now())
+
+	var blocked, running []pendingEvent
+	for _, p := range s.pending.all() {
+		if p.released {
+			running = append(running, p)
+		} else {
+			blocked = append(blocked, p)
+		}
+	}
+
+	if len(blocked) > 0 {
+		b.WriteString("\nBlocked events:\n")
+		for _, p := range blocked {
+			fmt.Fprintf(&b, "\t%s at %v\n", strings.Join(p.event.Tags(), ","), p.event.Time)
+			for _, blockingEvent := range configinternal.Events(p.blockingEvents) {
+				fmt.Fprintf(&b, "\t\twaiting for %T%+v\n", blockingEvent, blockingEvent)
+			}
+		}
+	}
+
+	if len(running) > 0 {
+		b.WriteString("\nRunning events:\n")
+		for _, p := range running {
+			fmt.Fprintf(&b, "\t%s at %v\n", strings.Join(p.event.Tags(), ","), p.event.Time)
+		}
+	}
+
+	expectations, ok := s.eventQueue.NewGeneratorsWaitGroups.Pending()
+	switch {
+	case !ok:
+		b.WriteString("\nExpected generators: unknown, they are being added\n")
+	case len(expectations) > 0:
+		b.WriteString("\nExpected generators not added yet:\n")
+		for _, expectation := range expectations {
+			fmt.Fprintf(&b, "\t%d tagged %s\n", expectation.Count, strings.Join(expectation.Tags, ","))
+		}
+	}
+
+	if stacks := labeledGoroutines(); stacks != "" {
+		b.WriteString("\nGoroutines of actions:\n")
+		b.WriteString(stacks)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// labeledGoroutines returns the stacks of the goroutines labeled with
+// watchdogLabel, grouped by identical stacks.
+func labeledGoroutines() string {
+	var profile bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+		return ""
+	}
+
+	var result []string
+	for _, group := range strings.Split(profile.String(), "\n\n") {
+		if strings.Contains(group, `"`+watchdogLabel+`"`) {
+			result = append(result, strings.TrimSpace(group))
+		}
+	}
+
+	return strings.Join(result, "\n\n")
+}
@@ -0,0 +1,98 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchdog(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("expected generator never added", func(t *testing.T) {
+		t.Parallel()
+
+		watchdogT := &fatalT{TB: t}
+		s := NewScheduler(now, Watchdog(watchdogT, 100*time.Millisecond))
+		s.ConfigureEvents(config.Config{
+			Tags: []string{"parent"},
+			Adds: []*config.Generator{{Tags: []string{"child"}, Count: 2}},
+		})
+
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+			s.PerformNow(ctx, timestone.SimpleAction(func(context.Context) {}), "child")
+		}), "parent")
+
+		report := watchdogT.report(func() { s.Forward(time.Minute) })
+		require.Contains(t, report, "waiting for expected generators")
+		require.Contains(t, report, "Expected generators not added yet:\n\t1 tagged child\n")
+	})
+
+	t.Run("blocked by running action", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		watchdogT := &fatalT{TB: t}
+		s := NewScheduler(now, Watchdog(watchdogT, 100*time.Millisecond))
+		s.ConfigureEvents(config.Config{
+			Tags:    []string{"waiting"},
+			WaitFor: []config.Event{config.All{Tags: []string{"stuck"}}},
+		})
+
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {
+			<-release
+		}), "stuck")
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "waiting")
+
+		report := watchdogT.report(func() { s.Forward(time.Minute) })
+		require.Contains(t, report, "waiting for all events")
+		require.Contains(t, report, "Blocked events:\n\twaiting at 2024-01-01 12:00:00 +0000 UTC\n\t\twaiting for config.All{Tags:[stuck]")
+		require.Contains(t, report, "Running events:\n\tstuck at 2024-01-01 12:00:00 +0000 UTC\n")
+		require.Contains(t, report, `"timestone.event":"stuck"`)
+		require.Contains(t, report, "TestWatchdog")
+	})
+
+	t.Run("completing in time", func(t *testing.T) {
+		t.Parallel()
+
+		watchdogT := &fatalT{TB: t}
+		s := NewScheduler(now, Watchdog(watchdogT, time.Second))
+		s.PerformNow(context.Background(), timestone.SimpleAction(func(context.Context) {}), "fast")
+
+		require.Empty(t, watchdogT.report(func() { s.Forward(time.Minute) }))
+	})
+}
+
+// fatalT records the failure of a watchdog instead of failing the test.
+type fatalT struct {
+	testing.TB
+	failure string
+}
+
+func (f *fatalT) Fatal(args ...any) {
+	f.failure = fmt.Sprint(args...)
+	runtime.Goexit()
+}
+
+// report calls forward on a new goroutine and returns the report the watchdog
+// has failed with, if any.
+func (f *fatalT) report(forward func()) string {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		forward()
+	}()
+	<-done
+
+	return f.failure
+}
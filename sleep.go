@@ -0,0 +1,56 @@
+package timestone
+
+import (
+	"context"
+	"time"
+)
+
+// sleeper is implemented by the Clock passed to an Action by a Scheduler
+// that controls how time passes for it, like the simulation.Scheduler.
+type sleeper interface {
+	After(ctx context.Context, duration time.Duration) <-chan time.Time
+}
+
+// After waits for duration to elapse on the Clock passed inside ctx to
+// the performing Action and then sends the current time on the returned
+// channel, unless ctx is done before.
+//
+// With a simulation.Scheduler, the Action is parked right away and
+// resumed by an event with its tags once the Scheduler has been forwarded
+// to the current time plus duration, or as soon as ctx is done. The run
+// loop treats a parked Action like it has finished, but waits for a
+// resumed Action to be parked again or to finish. Like for an Action
+// adding generators, use config.Config.Adds with the Action's own tags
+// to let the run loop wait for it to be parked in the first place.
+//
+// Without a Clock supporting this, After behaves like time.After.
+func After(ctx context.Context, duration time.Duration) <-chan time.Time {
+	if sleeper, ok := ctx.Value(ActionContextClockKey).(sleeper); ok {
+		return sleeper.After(ctx, duration)
+	}
+
+	c := make(chan time.Time, 1)
+
+	// Unregister from ctx once the timer has fired, so that long-lived
+	// contexts don't accumulate registrations.
+	stop := make(chan func() bool, 1)
+	timer := time.AfterFunc(duration, func() {
+		(<-stop)()
+		c <- time.Now()
+	})
+	stop <- context.AfterFunc(ctx, func() { timer.Stop() })
+
+	return c
+}
+
+// Sleep pauses the performing Action for at least duration as described
+// by After. It returns ctx.Err() if ctx is done before duration has
+// elapsed.
+func Sleep(ctx context.Context, duration time.Duration) error {
+	select {
+	case <-After(ctx, duration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
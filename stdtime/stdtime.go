@@ -0,0 +1,132 @@
+// Package stdtime mirrors the functions of the time package that depend
+// on the passing of time, resolving the clock or timestone.Scheduler to
+// use from a context.Context. Code migrated to it by changing its imports
+// and passing down a context.Context behaves like before in production,
+// while it can be tested with a simulation.Scheduler bound to the
+// context.Context by WithScheduler, e.g.
+//
+//	ctx := stdtime.WithScheduler(context.Background(), scheduler)
+//
+// Inside an Action, the timestone.Clock passed at
+// timestone.ActionContextClockKey takes precedence for Now, Sleep and
+// After, so that a simulation.Scheduler parks the Action while it
+// sleeps. Without either, the functions fall back to the time package.
+package stdtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/system"
+)
+
+// schedulerKey is the key of the timestone.Scheduler bound to a
+// context.Context by WithScheduler.
+type schedulerKey struct{}
+
+// WithScheduler returns a copy of parent that carries scheduler, to be
+// used by the functions of this package.
+func WithScheduler(parent context.Context, scheduler timestone.Scheduler) context.Context {
+	return context.WithValue(parent, schedulerKey{}, scheduler)
+}
+
+// Scheduler returns the timestone.Scheduler bound to ctx by
+// WithScheduler, or a system.Scheduler if there is none.
+func Scheduler(ctx context.Context) timestone.Scheduler {
+	if scheduler, ok := ctx.Value(schedulerKey{}).(timestone.Scheduler); ok {
+		return scheduler
+	}
+
+	return &system.Scheduler{}
+}
+
+// Now returns the current time of the timestone.Clock passed to the
+// performing Action, or of the timestone.Scheduler bound to ctx.
+func Now(ctx context.Context) time.Time {
+	if clock, ok := ctx.Value(timestone.ActionContextClockKey).(timestone.Clock); ok {
+		return clock.Now()
+	}
+
+	return Scheduler(ctx).Now()
+}
+
+// Since returns the time elapsed since t, measured like Now.
+func Since(ctx context.Context, t time.Time) time.Duration {
+	return Now(ctx).Sub(t)
+}
+
+// Until returns the duration until t, measured like Now.
+func Until(ctx context.Context, t time.Time) time.Duration {
+	return t.Sub(Now(ctx))
+}
+
+// Sleep pauses for at least duration, as measured by After. It returns
+// ctx.Err() if ctx is done before duration has elapsed.
+func Sleep(ctx context.Context, duration time.Duration) error {
+	if inAction(ctx) {
+		return timestone.Sleep(ctx, duration)
+	}
+
+	timer := NewTimer(ctx, duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// After waits for duration to elapse and then sends the current time on
+// the returned channel. Inside an Action, it behaves like
+// timestone.After, otherwise like the channel of a Timer returned by
+// NewTimer that is stopped once ctx is done.
+func After(ctx context.Context, duration time.Duration) <-chan time.Time {
+	if inAction(ctx) {
+		return timestone.After(ctx, duration)
+	}
+
+	c := make(chan time.Time, 1)
+
+	// Unregister from ctx once the Timer has fired, so that long-lived
+	// contexts don't accumulate registrations.
+	stop := make(chan func() bool, 1)
+	scheduler := Scheduler(ctx)
+	timer := timestone.NewTimerFunc(scheduler, duration, func() {
+		(<-stop)()
+		c <- scheduler.Now()
+	})
+	stop <- timestone.AfterFunc(ctx, func() { timer.Stop() })
+
+	return c
+}
+
+// Tick is like NewTicker, but returns the channel of the Ticker only. It
+// returns nil if interval is not positive, like time.Tick.
+func Tick(ctx context.Context, interval time.Duration) <-chan time.Time {
+	if interval <= 0 {
+		return nil
+	}
+
+	return NewTicker(ctx, interval).C
+}
+
+// NewTimer returns a timestone.Timer created by the timestone.Scheduler
+// bound to ctx.
+func NewTimer(ctx context.Context, duration time.Duration) *timestone.Timer {
+	return timestone.NewTimer(Scheduler(ctx), duration)
+}
+
+// NewTicker returns a timestone.Ticker created by the
+// timestone.Scheduler bound to ctx.
+func NewTicker(ctx context.Context, interval time.Duration) *timestone.Ticker {
+	return timestone.NewTicker(Scheduler(ctx), interval)
+}
+
+// inAction reports whether ctx has been passed to an Action.
+func inAction(ctx context.Context) bool {
+	_, ok := ctx.Value(timestone.ActionContextClockKey).(timestone.Clock)
+	return ok
+}
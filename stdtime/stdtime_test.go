@@ -0,0 +1,169 @@
+package stdtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/simulation"
+	"github.com/metamogul/timestone/v2/simulation/config"
+	"github.com/metamogul/timestone/v2/system"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock time.Time
+
+func (f fixedClock) Now() time.Time { return time.Time(f) }
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	s := simulation.NewScheduler(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	require.Same(t, s, Scheduler(WithScheduler(context.Background(), s)))
+	require.IsType(t, &system.Scheduler{}, Scheduler(context.Background()))
+}
+
+func TestNow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	actionNow := now.Add(time.Minute)
+	s := simulation.NewScheduler(now)
+
+	testcases := []struct {
+		name    string
+		ctx     context.Context
+		wantNow time.Time
+	}{
+		{
+			name:    "scheduler",
+			ctx:     WithScheduler(context.Background(), s),
+			wantNow: now,
+		},
+		{
+			name:    "action clock",
+			ctx:     context.WithValue(WithScheduler(context.Background(), s), timestone.ActionContextClockKey, fixedClock(actionNow)),
+			wantNow: actionNow,
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.wantNow, Now(tt.ctx))
+			require.Equal(t, time.Hour, Since(tt.ctx, tt.wantNow.Add(-time.Hour)))
+			require.Equal(t, time.Hour, Until(tt.ctx, tt.wantNow.Add(time.Hour)))
+		})
+	}
+}
+
+func TestNow_fallback(t *testing.T) {
+	t.Parallel()
+
+	before := time.Now()
+	now := Now(context.Background())
+
+	require.False(t, now.Before(before))
+	require.False(t, now.After(time.Now()))
+}
+
+func TestSleep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := simulation.NewScheduler(now)
+	s.ConfigureEvents(config.Config{
+		Tags: []string{"sleeping"},
+		Adds: []*config.Generator{{Tags: []string{"sleeping"}, Count: 1}},
+	})
+
+	var wokenAt time.Time
+	handle := s.PerformNowWithHandle(WithScheduler(context.Background(), s), timestone.SimpleAction(func(ctx context.Context) {
+		require.NoError(t, Sleep(ctx, time.Minute))
+		wokenAt = Now(ctx)
+	}), "sleeping")
+
+	s.Forward(time.Hour)
+
+	<-handle.Done()
+	require.Equal(t, now.Add(time.Minute), wokenAt)
+}
+
+func TestSleep_cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, Sleep(ctx, time.Hour), context.Canceled)
+}
+
+func TestAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := simulation.NewScheduler(now)
+	c := After(WithScheduler(context.Background(), s), time.Minute)
+
+	s.Forward(59 * time.Second)
+	require.Empty(t, c)
+
+	s.Forward(time.Second)
+	require.Equal(t, now.Add(time.Minute), <-c)
+}
+
+func TestAfter_cancelled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := simulation.NewScheduler(now)
+	ctx, cancel := timestone.WithTimeout(WithScheduler(context.Background(), s), s, 30*time.Second)
+	defer cancel()
+
+	c := After(ctx, time.Minute)
+
+	s.Forward(30 * time.Second)
+	require.Error(t, ctx.Err())
+
+	s.Forward(time.Hour)
+	require.Empty(t, c)
+	pending, err := s.PendingCount("")
+	require.NoError(t, err)
+	require.Zero(t, pending)
+}
+
+func TestTick(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := simulation.NewScheduler(now)
+	ctx := WithScheduler(context.Background(), s)
+
+	require.Nil(t, Tick(ctx, 0))
+
+	c := Tick(ctx, time.Minute)
+
+	s.Forward(time.Minute)
+	require.Equal(t, now.Add(time.Minute), <-c)
+
+	s.Forward(time.Minute)
+	require.Equal(t, now.Add(2*time.Minute), <-c)
+}
+
+func TestNewTimer_fallback(t *testing.T) {
+	t.Parallel()
+
+	timer := NewTimer(context.Background(), time.Millisecond)
+	<-timer.C
+
+	ticker := NewTicker(context.Background(), time.Millisecond)
+	defer ticker.Stop()
+	<-ticker.C
+}
@@ -0,0 +1,40 @@
+package system
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	ctx, cancel := timestone.WithTimeout(context.Background(), s, 10*time.Millisecond)
+	defer cancel()
+
+	afterFuncCalled := make(chan struct{})
+	timestone.AfterFunc(ctx, func() { close(afterFuncCalled) })
+
+	require.NoError(t, ctx.Err())
+
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	<-afterFuncCalled
+}
+
+func TestWithTimeout_cancel(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	ctx, cancel := timestone.WithTimeout(context.Background(), s, time.Hour)
+	cancel()
+
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,150 @@
+package system
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/internal"
+)
+
+// handle implements timestone.Handle for an Action scheduled by the
+// Scheduler, whose executions are performed one after another by run.
+type handle struct {
+	*internal.Lifecycle
+
+	clock     timestone.Clock
+	observers *internal.Observers
+	tags      []string
+
+	next     time.Time
+	hasNext  bool
+	schedule timestone.Schedule
+	// reschedule returns the Schedule to follow after an execution that
+	// has been rescheduled by Reset to from plus duration.
+	reschedule func(from time.Time, duration time.Duration) timestone.Schedule
+	// resets counts the calls to Reset, so that run notices them.
+	resets int
+
+	wake chan struct{}
+	mu   sync.Mutex
+}
+
+func newHandle(
+	ctx context.Context,
+	clock timestone.Clock,
+	observers *internal.Observers,
+	tags []string,
+	next time.Time,
+	hasNext bool,
+	schedule timestone.Schedule,
+	reschedule func(from time.Time, duration time.Duration) timestone.Schedule,
+) *handle {
+	if hasNext {
+		observers.Scheduled(tags, next)
+	}
+
+	return &handle{
+		Lifecycle: internal.NewObservedLifecycle(ctx, func() {
+			observers.Cancelled(tags, clock.Now())
+		}),
+		clock:      clock,
+		observers:  observers,
+		tags:       tags,
+		next:       next,
+		hasNext:    hasNext,
+		schedule:   schedule,
+		reschedule: reschedule,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+func (h *handle) Reset(duration time.Duration) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.Revive() {
+		return false
+	}
+
+	now := h.clock.Now()
+	h.next, h.hasNext = now.Add(duration), true
+	h.schedule = h.reschedule(now, duration)
+	h.resets++
+
+	h.observers.Scheduled(h.tags, h.next)
+
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+
+	return true
+}
+
+func (h *handle) run(ctx context.Context, action timestone.Action) {
+	for {
+		h.mu.Lock()
+		if !h.hasNext {
+			h.Exhaust()
+			h.mu.Unlock()
+			return
+		}
+		next, resets := h.next, h.resets
+		h.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-timer.C:
+		case <-h.wake:
+			timer.Stop()
+			continue
+		case <-h.Done():
+			timer.Stop()
+			return
+		}
+
+		h.mu.Lock()
+		if h.resets != resets {
+			h.mu.Unlock()
+			continue
+		}
+		h.mu.Unlock()
+
+		h.observers.Released(h.tags, h.clock.Now())
+
+		if !h.Start() {
+			return
+		}
+
+		h.perform(ctx, action)
+
+		h.mu.Lock()
+		if h.resets == resets {
+			// Like time.Ticker, skip executions that have been missed
+			// while performing the action.
+			after := next
+			if now := h.clock.Now(); now.After(after) {
+				after = now
+			}
+			h.next, h.hasNext = h.schedule.Next(after)
+			if h.hasNext {
+				h.observers.Scheduled(h.tags, h.next)
+			}
+		}
+		h.mu.Unlock()
+
+		h.Finish()
+	}
+}
+
+// perform performs an execution of action, notifying the observers.
+func (h *handle) perform(ctx context.Context, action timestone.Action) {
+	defer h.observers.Recover(h.tags, h.clock)
+
+	h.observers.Started(h.tags, h.clock.Now())
+	action.Perform(context.WithValue(ctx, timestone.ActionContextClockKey, h.clock))
+	h.observers.Finished(h.tags, h.clock.Now())
+}
@@ -0,0 +1,109 @@
+package system
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandle_Cancel(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+	handle := s.PerformAfterWithHandle(context.Background(), timestone.NewMockAction(t), 10*time.Millisecond)
+
+	handle.Cancel()
+
+	<-handle.Done()
+	require.Equal(t, timestone.StatusCancelled, handle.Status())
+	require.False(t, handle.Reset(time.Millisecond))
+
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, 0, handle.Runs())
+}
+
+func TestHandle_Cancel_whileRunning(t *testing.T) {
+	t.Parallel()
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	s := &Scheduler{Clock: Clock{}}
+	handle := s.PerformRepeatedlyWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {
+		close(started)
+		<-release
+	}), nil, time.Millisecond)
+
+	<-started
+	require.Equal(t, timestone.StatusRunning, handle.Status())
+
+	handle.Cancel()
+	select {
+	case <-handle.Done():
+		require.Fail(t, "handle is done while the action is running")
+	default:
+	}
+
+	close(release)
+	<-handle.Done()
+	require.Equal(t, 1, handle.Runs())
+}
+
+func TestHandle_Reset(t *testing.T) {
+	t.Parallel()
+
+	var runs atomic.Int32
+
+	s := &Scheduler{Clock: Clock{}}
+	handle := s.PerformAfterWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {
+		runs.Add(1)
+	}), 20*time.Millisecond)
+
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, handle.Reset(time.Hour))
+
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, int32(0), runs.Load())
+	require.Equal(t, timestone.StatusScheduled, handle.Status())
+
+	require.True(t, handle.Reset(time.Millisecond))
+	<-handle.Done()
+	require.Equal(t, int32(1), runs.Load())
+	require.Equal(t, timestone.StatusFinished, handle.Status())
+	require.False(t, handle.Reset(time.Millisecond))
+}
+
+func TestHandle_Reset_fromAction(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	var handle timestone.Handle
+	rearmed := make(chan struct{})
+	handle = s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {
+		<-rearmed
+		if handle.Runs() < 3 {
+			handle.Reset(time.Millisecond)
+		}
+	}))
+	close(rearmed)
+
+	<-handle.Done()
+	require.Equal(t, 3, handle.Runs())
+}
+
+func TestHandle_Reset_periodic(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+	handle := s.PerformRepeatedlyWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {}), nil, time.Hour)
+
+	require.True(t, handle.Reset(time.Millisecond))
+
+	require.Eventually(t, func() bool { return handle.Runs() >= 3 }, time.Second, time.Millisecond)
+	handle.Cancel()
+	<-handle.Done()
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/metamogul/timestone/v2"
+	"github.com/metamogul/timestone/v2/cron"
+	"github.com/metamogul/timestone/v2/internal"
+	"github.com/metamogul/timestone/v2/schedule"
 )
 
 type Clock struct{}
@@ -13,52 +16,122 @@ func (c Clock) Now() time.Time {
 	return time.Now()
 }
 
+var (
+	_ timestone.HandleScheduler   = (*Scheduler)(nil)
+	_ timestone.RealTimeScheduler = (*Scheduler)(nil)
+)
+
 type Scheduler struct {
 	Clock
+
+	observers internal.Observers
 }
 
-func (s *Scheduler) PerformNow(ctx context.Context, action timestone.Action, _ ...string) {
-	go func() {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			action.Perform(context.WithValue(ctx, timestone.ActionContextClockKey, s.Clock))
-		}
-	}()
+// Observe attaches observer to be notified about the lifecycle of the
+// Action s scheduled from now on.
+func (s *Scheduler) Observe(observer timestone.Observer) {
+	s.observers.Add(observer)
+}
+
+func (s *Scheduler) PerformNow(ctx context.Context, action timestone.Action, tags ...string) {
+	s.PerformNowWithHandle(ctx, action, tags...)
+}
+
+func (s *Scheduler) PerformNowWithHandle(ctx context.Context, action timestone.Action, tags ...string) timestone.Handle {
+	return s.perform(ctx, action, tags, s.Now(), true, schedule.At(), once)
+}
+
+func (s *Scheduler) PerformAfter(ctx context.Context, action timestone.Action, duration time.Duration, tags ...string) {
+	s.PerformAfterWithHandle(ctx, action, duration, tags...)
 }
 
-func (s *Scheduler) PerformAfter(ctx context.Context, action timestone.Action, duration time.Duration, _ ...string) {
-	go func() {
-		select {
-		case <-time.After(duration):
-			action.Perform(context.WithValue(ctx, timestone.ActionContextClockKey, s.Clock))
-		case <-ctx.Done():
-			return
+func (s *Scheduler) PerformAfterWithHandle(ctx context.Context, action timestone.Action, duration time.Duration, tags ...string) timestone.Handle {
+	return s.perform(ctx, action, tags, s.Now().Add(duration), true, schedule.At(), once)
+}
+
+func (s *Scheduler) PerformRepeatedly(ctx context.Context, action timestone.Action, until *time.Time, interval time.Duration, tags ...string) {
+	s.PerformRepeatedlyWithHandle(ctx, action, until, interval, tags...)
+}
+
+func (s *Scheduler) PerformRepeatedlyWithHandle(ctx context.Context, action timestone.Action, until *time.Time, interval time.Duration, tags ...string) timestone.Handle {
+	repeatedly := func(from time.Time, interval time.Duration) timestone.Schedule {
+		every := schedule.Every(from, interval)
+		if until == nil {
+			return every
 		}
-	}()
+
+		return schedule.Until(every, *until)
+	}
+
+	now := s.Now()
+	repetitions := repeatedly(now, interval)
+	next, ok := repetitions.Next(now)
+
+	return s.perform(ctx, action, tags, next, ok, repetitions, repeatedly)
 }
 
-func (s *Scheduler) PerformRepeatedly(ctx context.Context, action timestone.Action, until *time.Time, interval time.Duration, _ ...string) {
-	ticker := time.NewTicker(interval)
+func (s *Scheduler) PerformOnSchedule(ctx context.Context, action timestone.Action, schedule string, tags ...string) error {
+	_, err := s.PerformOnScheduleWithHandle(ctx, action, schedule, tags...)
+	return err
+}
 
-	var timer *time.Timer
-	if until != nil {
-		timer = time.NewTimer(until.Sub(s.Now()))
-	} else {
-		timer = &time.Timer{}
+func (s *Scheduler) PerformOnScheduleWithHandle(ctx context.Context, action timestone.Action, schedule string, tags ...string) (timestone.Handle, error) {
+	cronSchedule, err := cron.Parse(schedule)
+	if err != nil {
+		return nil, err
 	}
 
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				action.Perform(context.WithValue(ctx, timestone.ActionContextClockKey, s.Clock))
-			case <-timer.C:
-				return
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
+	return s.PerformScheduledWithHandle(ctx, action, cronSchedule, tags...), nil
+}
+
+func (s *Scheduler) PerformScheduled(ctx context.Context, action timestone.Action, schedule timestone.Schedule, tags ...string) {
+	s.PerformScheduledWithHandle(ctx, action, schedule, tags...)
+}
+
+func (s *Scheduler) PerformScheduledWithHandle(ctx context.Context, action timestone.Action, schedule timestone.Schedule, tags ...string) timestone.Handle {
+	next, ok := schedule.Next(s.Now())
+
+	return s.perform(ctx, action, tags, next, ok, schedule, func(time.Time, time.Duration) timestone.Schedule {
+		return schedule
+	})
+}
+
+// RealTimeAfterFunc implements timestone.RealTimeScheduler, so that the
+// Timer s and Ticker s created for the Scheduler use the time package.
+func (s *Scheduler) RealTimeAfterFunc(duration time.Duration, f func()) *time.Timer {
+	return time.AfterFunc(duration, f)
+}
+
+func (s *Scheduler) NewTimer(duration time.Duration, tags ...string) *timestone.Timer {
+	return timestone.NewTimer(s, duration, tags...)
+}
+
+func (s *Scheduler) NewTicker(interval time.Duration, tags ...string) *timestone.Ticker {
+	return timestone.NewTicker(s, interval, tags...)
+}
+
+func (s *Scheduler) AfterFunc(duration time.Duration, f func(), tags ...string) *timestone.Timer {
+	return timestone.NewTimerFunc(s, duration, f, tags...)
+}
+
+// perform runs action at next, if hasNext, and at every point in time of
+// schedule afterwards.
+func (s *Scheduler) perform(
+	ctx context.Context,
+	action timestone.Action,
+	tags []string,
+	next time.Time,
+	hasNext bool,
+	schedule timestone.Schedule,
+	reschedule func(from time.Time, duration time.Duration) timestone.Schedule,
+) timestone.Handle {
+	h := newHandle(ctx, s.Clock, &s.observers, tags, next, hasNext, schedule, reschedule)
+	go h.run(ctx, action)
+
+	return h
+}
+
+// once is used to reschedule Action s that are to be performed only once.
+func once(time.Time, time.Duration) timestone.Schedule {
+	return schedule.At()
 }
@@ -2,12 +2,16 @@ package system
 
 import (
 	"context"
+	"fmt"
+	"github.com/metamogul/timestone/v2/cron"
 	"github.com/metamogul/timestone/v2/internal"
+	"github.com/metamogul/timestone/v2/schedule"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
 )
 
 func TestScheduler_PerformNow(t *testing.T) {
@@ -94,19 +98,55 @@ func TestScheduler_PerformRepeatedly_until(t *testing.T) {
 	wg.Wait()
 }
 
-func TestScheduler_PerformRepeatedly_indefinitely(t *testing.T) {
+func TestScheduler_PerformRepeatedly_untilPassedWhileRunning(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	clock := Clock{}
 
+	performed := make(chan struct{})
+
+	mockAction := timestone.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(context.WithValue(ctx, timestone.ActionContextClockKey, clock)).
+		Run(func(context.Context) {
+			// Run past until while the next repetition becomes due.
+			time.Sleep(3 * time.Millisecond)
+			close(performed)
+		}).
+		Once()
+
+	s := &Scheduler{Clock: clock}
+	s.PerformRepeatedly(ctx, mockAction, internal.Ptr(clock.Now().Add(1500*time.Microsecond)), time.Millisecond)
+	<-performed
+	time.Sleep(3 * time.Millisecond)
+}
+
+func TestScheduler_PerformRepeatedly_indefinitely(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := Clock{}
+
+	wg := &sync.WaitGroup{}
+	calls := 0
+
 	mockAction := timestone.NewMockAction(t)
 	mockAction.EXPECT().
 		Perform(context.WithValue(ctx, timestone.ActionContextClockKey, clock)).
+		Run(func(context.Context) {
+			calls++
+			if calls == 2 {
+				cancel()
+			}
+			wg.Done()
+		}).
 		Twice()
 
 	s := &Scheduler{Clock: Clock{}}
+	wg.Add(2)
 	s.PerformRepeatedly(ctx, mockAction, nil, time.Millisecond)
+	wg.Wait()
 	time.Sleep(3 * time.Millisecond)
 }
 
@@ -121,3 +161,167 @@ func TestScheduler_PerformRepeatedly_cancelled(t *testing.T) {
 	s.PerformRepeatedly(ctx, timestone.NewMockAction(t), internal.Ptr(clock.Now().Add(3*time.Millisecond)), time.Millisecond)
 	time.Sleep(2 * time.Millisecond)
 }
+
+func TestScheduler_PerformRepeatedly_cancelledWhileRunning(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := Clock{}
+
+	performed := make(chan struct{})
+
+	mockAction := timestone.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(context.WithValue(ctx, timestone.ActionContextClockKey, clock)).
+		Run(func(context.Context) {
+			cancel()
+			// Let further repetitions become due while running.
+			time.Sleep(3 * time.Millisecond)
+			close(performed)
+		}).
+		Once()
+
+	s := &Scheduler{Clock: clock}
+	s.PerformRepeatedly(ctx, mockAction, nil, time.Millisecond)
+	<-performed
+	time.Sleep(3 * time.Millisecond)
+}
+
+func TestScheduler_PerformAfter_observed(t *testing.T) {
+	t.Parallel()
+
+	observer := &recordingObserver{notifications: make(chan string, 16)}
+
+	s := &Scheduler{Clock: Clock{}}
+	s.Observe(observer)
+
+	s.PerformAfter(context.Background(), timestone.SimpleAction(func(context.Context) {}), time.Millisecond, "performed")
+
+	want := []string{
+		"scheduled [performed]",
+		"released [performed]",
+		"started [performed]",
+		"finished [performed]",
+	}
+	for _, notification := range want {
+		require.Equal(t, notification, <-observer.notifications)
+	}
+}
+
+func TestScheduler_PerformOnSchedule(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := Clock{}
+
+	wg := &sync.WaitGroup{}
+
+	mockAction := timestone.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(context.WithValue(ctx, timestone.ActionContextClockKey, clock)).
+		Run(func(context.Context) { wg.Done() }).
+		Once()
+
+	s := &Scheduler{Clock: clock}
+	wg.Add(1)
+	err := s.PerformOnSchedule(ctx, mockAction, "* * * * * *")
+	require.NoError(t, err)
+	wg.Wait()
+	cancel()
+}
+
+func TestScheduler_PerformOnSchedule_invalidSchedule(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+	err := s.PerformOnSchedule(context.Background(), timestone.NewMockAction(t), "* * *")
+	require.ErrorIs(t, err, cron.ErrInvalidSpec)
+}
+
+func TestScheduler_PerformOnSchedule_cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := &Scheduler{Clock: Clock{}}
+	err := s.PerformOnSchedule(ctx, timestone.NewMockAction(t), "* * * * * *")
+	require.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+}
+
+func TestScheduler_PerformScheduled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := Clock{}
+
+	wg := &sync.WaitGroup{}
+
+	mockAction := timestone.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(context.WithValue(ctx, timestone.ActionContextClockKey, clock)).
+		Run(func(context.Context) { wg.Done() }).
+		Times(3)
+
+	s := &Scheduler{Clock: clock}
+	wg.Add(3)
+	s.PerformScheduled(ctx, mockAction, schedule.Limit(schedule.Every(clock.Now(), time.Millisecond), clock.Now(), 3))
+	wg.Wait()
+	time.Sleep(2 * time.Millisecond)
+}
+
+func TestScheduler_PerformScheduled_cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	clock := Clock{}
+
+	s := &Scheduler{Clock: clock}
+	s.PerformScheduled(ctx, timestone.NewMockAction(t), schedule.Every(clock.Now(), time.Millisecond))
+	time.Sleep(2 * time.Millisecond)
+}
+
+type recordingObserver struct {
+	timestone.NopObserver
+	notifications chan string
+}
+
+func (o *recordingObserver) OnScheduled(tags []string, _ time.Time) { o.record("scheduled", tags) }
+func (o *recordingObserver) OnReleased(tags []string, _ time.Time)  { o.record("released", tags) }
+func (o *recordingObserver) OnStarted(tags []string, _ time.Time)   { o.record("started", tags) }
+func (o *recordingObserver) OnFinished(tags []string, _ time.Time)  { o.record("finished", tags) }
+func (o *recordingObserver) OnCancelled(tags []string, _ time.Time) { o.record("cancelled", tags) }
+
+func (o *recordingObserver) record(kind string, tags []string) {
+	o.notifications <- fmt.Sprintf("%s %v", kind, tags)
+}
+
+func TestScheduler_Observe(t *testing.T) {
+	t.Parallel()
+
+	observer := &recordingObserver{notifications: make(chan string, 16)}
+
+	s := &Scheduler{Clock: Clock{}}
+	s.Observe(observer)
+
+	performed := s.PerformAfterWithHandle(context.Background(), timestone.SimpleAction(func(context.Context) {}), time.Millisecond, "performed")
+	<-performed.Done()
+
+	cancelled := s.PerformAfterWithHandle(context.Background(), timestone.NewMockAction(t), time.Hour, "cancelled")
+	cancelled.Cancel()
+	<-cancelled.Done()
+
+	want := []string{
+		"scheduled [performed]",
+		"released [performed]",
+		"started [performed]",
+		"finished [performed]",
+		"scheduled [cancelled]",
+		"cancelled [cancelled]",
+	}
+	for _, notification := range want {
+		require.Equal(t, notification, <-observer.notifications)
+	}
+}
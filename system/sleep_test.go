@@ -0,0 +1,68 @@
+package system
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timestone/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSleep(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	var slept time.Duration
+	handle := s.PerformNowWithHandle(context.Background(), timestone.SimpleAction(func(ctx context.Context) {
+		start := time.Now()
+		require.NoError(t, timestone.Sleep(ctx, 10*time.Millisecond))
+		slept = time.Since(start)
+	}))
+
+	<-handle.Done()
+	require.GreaterOrEqual(t, slept, 10*time.Millisecond)
+}
+
+func TestSleep_cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, timestone.Sleep(ctx, time.Hour), context.Canceled)
+}
+
+// afterFuncContext reports whether the function registered by
+// context.AfterFunc has been stopped. It needs its own Done channel for
+// context.AfterFunc to use its AfterFunc method.
+type afterFuncContext struct {
+	context.Context
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func (c *afterFuncContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *afterFuncContext) AfterFunc(func()) (stop func() bool) {
+	return func() bool {
+		close(c.stopped)
+		return true
+	}
+}
+
+func TestAfter_stopsAfterFunc(t *testing.T) {
+	t.Parallel()
+
+	ctx := &afterFuncContext{
+		Context: context.Background(),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	<-timestone.After(ctx, time.Millisecond)
+	<-ctx.stopped
+}
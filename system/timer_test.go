@@ -0,0 +1,70 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_NewTimer(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	start := time.Now()
+	timer := s.NewTimer(10 * time.Millisecond)
+
+	firedAt := <-timer.C
+	require.GreaterOrEqual(t, firedAt.Sub(start), 10*time.Millisecond)
+	require.False(t, timer.Stop())
+}
+
+func TestTimer_Stop(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	timer := s.NewTimer(10 * time.Millisecond)
+	require.True(t, timer.Stop())
+
+	time.Sleep(20 * time.Millisecond)
+	require.Empty(t, timer.C)
+}
+
+func TestTimer_Reset(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	timer := s.NewTimer(time.Hour)
+	require.True(t, timer.Reset(10*time.Millisecond))
+
+	<-timer.C
+	require.False(t, timer.Reset(10*time.Millisecond))
+	<-timer.C
+}
+
+func TestScheduler_AfterFunc(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	called := make(chan struct{})
+	s.AfterFunc(10*time.Millisecond, func() { close(called) })
+
+	<-called
+}
+
+func TestScheduler_NewTicker(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{Clock: Clock{}}
+
+	ticker := s.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	first := <-ticker.C
+	second := <-ticker.C
+	require.True(t, second.After(first))
+}
@@ -0,0 +1,298 @@
+package timestone
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RealTimeScheduler is a Scheduler whose clock follows the real time,
+// like the system.Scheduler. The Timer s and Ticker s created for it are
+// backed by a time.Timer rather than by Action s it performs.
+type RealTimeScheduler interface {
+	Scheduler
+	// RealTimeAfterFunc waits for duration to elapse and then calls f in
+	// its own goroutine, like time.AfterFunc.
+	RealTimeAfterFunc(duration time.Duration, f func()) *time.Timer
+}
+
+// Timer is the equivalent of a time.Timer for a Scheduler, as returned by
+// NewTimer and NewTimerFunc. Unless the Scheduler is a RealTimeScheduler,
+// it is backed by an Action scheduled via Scheduler.PerformAfter, so that
+// with a simulation.Scheduler it fires once Forward passes its expiry,
+// ordered like any other event at the same time.
+//
+// Like a time.Timer before Go 1.23, C is buffered, but Stop and Reset
+// discard a value that has not been received yet, so that no stale value
+// is received after they have returned.
+type Timer struct {
+	// C delivers the time of the Scheduler's clock when the Timer fires.
+	// It is nil for a Timer created by NewTimerFunc.
+	C <-chan time.Time
+
+	c         chan time.Time
+	f         func()
+	scheduler Scheduler
+	tags      []string
+
+	// active is true from starting the Timer until it fires or is
+	// stopped.
+	active bool
+	// cancel releases what backs the Timer since it has been started.
+	cancel func()
+	// generation is incremented by Stop and Reset, so that the Action
+	// scheduled before notices that it must not fire anymore.
+	generation int
+	mu         sync.Mutex
+}
+
+// NewTimer creates a Timer that sends the current time of the Clock of
+// scheduler on its channel after duration. The Action firing it is
+// scheduled with tags.
+func NewTimer(scheduler Scheduler, duration time.Duration, tags ...string) *Timer {
+	c := make(chan time.Time, 1)
+	t := &Timer{
+		C:         c,
+		c:         c,
+		scheduler: scheduler,
+		tags:      tags,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start(duration)
+
+	return t
+}
+
+// NewTimerFunc creates a Timer that calls f after duration, like
+// time.AfterFunc. Unless scheduler is a RealTimeScheduler, f is called by
+// the Action firing the Timer rather than in its own goroutine, which is
+// scheduled with tags.
+func NewTimerFunc(scheduler Scheduler, duration time.Duration, f func(), tags ...string) *Timer {
+	t := &Timer{
+		f:         f,
+		scheduler: scheduler,
+		tags:      tags,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start(duration)
+
+	return t
+}
+
+// Stop prevents the Timer from firing. Like time.Timer.Stop, it returns
+// true if the call stops the Timer, false if it has already fired or been
+// stopped. An Action firing the Timer that is already running when Stop
+// is called counts as prevented.
+func (t *Timer) Stop() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.stop()
+}
+
+// Reset changes the Timer to fire after duration, measured from the
+// current time of the Scheduler's clock. It returns true if the Timer had
+// been active, false if it had fired or been stopped.
+func (t *Timer) Reset(duration time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	active := t.stop()
+	t.start(duration)
+
+	return active
+}
+
+func (t *Timer) start(duration time.Duration) {
+	t.active = true
+	generation := t.generation
+
+	if scheduler, ok := t.scheduler.(RealTimeScheduler); ok {
+		timer := scheduler.RealTimeAfterFunc(duration, func() {
+			t.fire(scheduler, generation)
+		})
+		t.cancel = func() { timer.Stop() }
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.scheduler.PerformAfter(ctx, SimpleAction(func(ctx context.Context) {
+		t.fire(ctx.Value(ActionContextClockKey).(Clock), generation)
+	}), duration, t.tags...)
+}
+
+func (t *Timer) stop() bool {
+	active := t.active
+	t.active = false
+	t.cancel()
+	t.generation++
+	drain(t.c)
+
+	return active
+}
+
+func (t *Timer) fire(clock Clock, generation int) {
+	t.mu.Lock()
+
+	if generation != t.generation {
+		t.mu.Unlock()
+		return
+	}
+	t.active = false
+
+	if t.f == nil {
+		t.c <- clock.Now()
+		t.mu.Unlock()
+		return
+	}
+
+	t.mu.Unlock()
+	t.f()
+}
+
+// Ticker is the equivalent of a time.Ticker for a Scheduler, as returned
+// by NewTicker. Unless the Scheduler is a RealTimeScheduler, it is backed
+// by an Action scheduled via Scheduler.PerformRepeatedly, and like a Timer
+// discards a value that has not been received yet on Stop and Reset.
+type Ticker struct {
+	// C delivers the time of the Scheduler's clock at every tick. Unlike
+	// with a time.Ticker, a tick that hasn't been received yet is
+	// replaced by the following one, so that the value received doesn't
+	// depend on the order in which a simulation.Scheduler performs ticks
+	// at different times running concurrently.
+	C <-chan time.Time
+
+	c         chan time.Time
+	scheduler Scheduler
+	tags      []string
+
+	// cancel releases what backs the Ticker since it has been started.
+	cancel     func()
+	generation int
+	last       time.Time
+	mu         sync.Mutex
+}
+
+// NewTicker creates a Ticker that sends the current time of the Clock of
+// scheduler on its channel every interval. The Action performing the
+// ticks is scheduled with tags. NewTicker panics if interval is not
+// positive, like time.NewTicker.
+func NewTicker(scheduler Scheduler, interval time.Duration, tags ...string) *Ticker {
+	if interval <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	c := make(chan time.Time, 1)
+	t := &Ticker{
+		C:         c,
+		c:         c,
+		scheduler: scheduler,
+		tags:      tags,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start(interval)
+
+	return t
+}
+
+// Stop turns off the Ticker until it is restarted by Reset. Like with a
+// time.Ticker, C is not closed.
+func (t *Ticker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stop()
+}
+
+// Reset stops the Ticker and resets its interval to interval, with the
+// next tick taking place after interval. It panics if interval is not
+// positive, like time.Ticker.Reset.
+func (t *Ticker) Reset(interval time.Duration) {
+	if interval <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stop()
+	t.start(interval)
+}
+
+func (t *Ticker) start(interval time.Duration) {
+	generation := t.generation
+
+	if scheduler, ok := t.scheduler.(RealTimeScheduler); ok {
+		t.startRealTime(scheduler, interval, scheduler.Now().Add(interval), generation)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.scheduler.PerformRepeatedly(ctx, SimpleAction(func(ctx context.Context) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.tick(ctx.Value(ActionContextClockKey).(Clock), generation)
+	}), nil, interval, t.tags...)
+}
+
+// startRealTime schedules the next tick at next, and the ones after it
+// every interval. Like with a time.Ticker, ticks that have been missed
+// are skipped.
+func (t *Ticker) startRealTime(scheduler RealTimeScheduler, interval time.Duration, next time.Time, generation int) {
+	timer := scheduler.RealTimeAfterFunc(next.Sub(scheduler.Now()), func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if generation != t.generation {
+			return
+		}
+
+		t.tick(scheduler, generation)
+
+		for now := scheduler.Now(); !next.After(now); {
+			next = next.Add(interval)
+		}
+		t.startRealTime(scheduler, interval, next, generation)
+	})
+	t.cancel = func() { timer.Stop() }
+}
+
+func (t *Ticker) stop() {
+	t.cancel()
+	t.generation++
+	t.last = time.Time{}
+	drain(t.c)
+}
+
+func (t *Ticker) tick(clock Clock, generation int) {
+	if generation != t.generation {
+		return
+	}
+
+	now := clock.Now()
+	if now.Before(t.last) {
+		return
+	}
+	t.last = now
+
+	drain(t.c)
+	t.c <- now
+}
+
+// drain discards a value that hasn't been received from c yet.
+func drain(c chan time.Time) {
+	select {
+	case <-c:
+	default:
+	}
+}